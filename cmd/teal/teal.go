@@ -30,12 +30,24 @@ Commands:
 	            --config-file string     Path to config.yaml (default "config.yaml")
 	            --model string          Name of target model (optional)
 
+	check     Check that every raw asset has a registered executor
+	          Flags:
+	            --project-path string    Project directory (default ".")
+
+	test      Run the unit tests of assets/unit_tests in an in-memory DuckDB
+	          Flags:
+	            --unit                  Run the unit tests (required)
+	            --project-path string    Project directory (default ".")
+	            --config-file string     Path to config.yaml (default "config.yaml")
+	            --select string         Unit tests or models to test (optional)
+
 	clean     Clean generated files
 	          Flags:
 	            --project-path string    Project directory (default ".")
 	            --model string          Models for cleaning (default "*")
 	            --clean-main            Delete production main.go
 	            --clean-main-ui         Delete UI debug main.go
+	            --clean-main-unit       Delete unit tests main.go
 	            --clean-dockerfile      Delete Dockerfile
 	            --clean-go-mod          Delete go.mod and go.sum
 	            --clean-all             Delete ALL generated files
@@ -55,6 +67,8 @@ Global Flags:
 Examples:
 	teal init
 	teal gen --project-path ./my-project
+	teal check
+	teal test --unit
 	teal clean --clean-main
 	teal ui --port 9090
 	teal version
@@ -92,6 +106,46 @@ Examples:
   teal gen --project-path ./my-project
   teal gen --model staging.customers
   teal gen --config-file custom-config.yaml
+`,
+		"check": `
+Usage: teal check [flags]
+
+Scans the Go sources of the project for the registrations of raw asset executors
+(processing.RegisterExecutor, processing.MustRegisterExecutor and
+processing.GetExecutors().Execurots[...]) and compares them with the raw assets of profile.yaml.
+
+Flags:
+  --project-path string    Project directory (default ".")
+
+Reports:
+  MISSING     raw asset without an executor (fails the check)
+  DUPLICATE   executor registered more than once (fails the check)
+  UNKNOWN     executor registered for a name which is not a raw asset
+  DYNAMIC     registration with a non-literal name, can not be checked
+
+Examples:
+  teal check
+  teal check --project-path ./my-project
+`,
+		"test": `
+Usage: teal test --unit [flags]
+
+Regenerates the project and runs the unit tests of assets/unit_tests with
+go run ./cmd/<project-name>-unit. Every unit test creates its fixtures in a fresh
+in-memory DuckDB, runs the model against them and compares the rows with the
+expected rows. The differing rows are printed, "-" for the missing rows and
+"+" for the unexpected ones.
+
+Flags:
+  --unit                  Run the unit tests (required)
+  --project-path string    Project directory (default ".")
+  --config-file string     Path to config.yaml (default "config.yaml")
+  --select string         Names of unit tests or of models, separated by commas (optional)
+
+Examples:
+  teal test --unit
+  teal test --unit --select dds.dim_airports
+  teal test --unit --select dds.dim_airports_codes
 `,
 		"clean": `
 Usage: teal clean [flags]
@@ -103,6 +157,7 @@ Flags:
   --model string          Models for cleaning (default "*" for all)
   --clean-main            Delete production main.go in cmd/<project-name>/
   --clean-main-ui         Delete UI debug main.go in cmd/<project-name>-ui/
+  --clean-main-unit       Delete unit tests main.go in cmd/<project-name>-unit/
   --clean-dockerfile      Delete Dockerfile
   --clean-go-mod          Delete go.mod and go.sum
   --clean-all             Delete ALL generated files (prompts for confirmation)
@@ -112,6 +167,7 @@ Examples:
   teal clean --model staging.customers     # Clean specific model
   teal clean --clean-main                  # Clean production main.go only
   teal clean --clean-main-ui               # Clean UI main.go only
+  teal clean --clean-main-unit             # Clean unit tests main.go only
   teal clean --clean-dockerfile            # Clean Dockerfile only
   teal clean --clean-go-mod                # Clean go.mod and go.sum
   teal clean --clean-all                   # Clean everything (prompts for confirmation)
@@ -120,7 +176,7 @@ Examples:
 Note:
 - When cleaning all models (*), you will be prompted for confirmation
 - --clean-all will delete ALL generated files including go.mod, Dockerfile, and main files
-- Files not overwritten during 'teal gen': Dockerfile, go.mod, production main.go, UI main.go
+- Files not overwritten during 'teal gen': Dockerfile, go.mod, production main.go, UI main.go, unit tests main.go
 `,
 		"ui": `
 Usage: teal ui [flags]
@@ -174,10 +230,12 @@ func root(args []string) error {
 	app := application.InitApplication()
 	cmds := []Runner{
 		commands.NewGenCommand(app),
+		commands.NewCheckCommand(app),
 		commands.NewCleanCommand(app),
 		commands.NewVersionCommand(app),
 		commands.NewInitCommand(app),
 		commands.NewUICommand(app),
+		commands.NewTestCommand(app),
 	}
 
 	subcommand := os.Args[1]
@@ -26,13 +26,17 @@ func (app *Application) GenegateAssets(pojectPath string, configFilePath string,
 	}
 
 	var generatorsList []generators.Generator = []generators.Generator{
-		generators.InitGenMain(config, projectProfile),   // Production main.go
-		generators.InitGenMainUI(config, projectProfile), // UI debugging main.go
+		generators.InitGenMain(config, projectProfile),     // Production main.go
+		generators.InitGenMainUI(config, projectProfile),   // UI debugging main.go
+		generators.InitGenMainUnit(config, projectProfile), // Unit tests main.go
 		generators.InitGenGoMod(config, projectProfile),
 		generators.InitGenMakefile(config, projectProfile),   // Makefile
 		generators.InitGenDockerfile(config, projectProfile), // Dockerfile
 	}
 
+	services.InitMacros(config, projectProfile)
+	services.InitSourceProfiles(projectProfile)
+	services.InitSeedProfiles(config, projectProfile)
 	services.CombineProfiles(config, projectProfile)
 	modelConfigs, err := services.InitSQLModelConfigs(config, projectProfile)
 	if err != nil {
@@ -48,19 +52,47 @@ func (app *Application) GenegateAssets(pojectPath string, configFilePath string,
 
 	modelConfigs = append(modelConfigs, modelConfigs2...)
 
+	seedConfigs, err := services.InitSeedConfigs(config, projectProfile)
+	if err != nil {
+		fmt.Printf("can not create a configuration for seeds %v\n", err)
+		return err
+	}
+
+	modelConfigs = append(modelConfigs, seedConfigs...)
+
+	sourceConfigs, err := services.InitSourceConfigs(config, projectProfile)
+	if err != nil {
+		fmt.Printf("can not create a configuration for sources %v\n", err)
+		return err
+	}
+
+	modelConfigs = append(modelConfigs, sourceConfigs...)
+
 	for _, modelConfig := range modelConfigs {
 		fmt.Printf("%s <- %v\n", modelConfig.ModelName, modelConfig.Upstreams)
 		switch modelConfig.ModelType {
 		case internalmodels.DATABASE:
+			if modelConfig.ModelProfile.Materialization == configs.MAT_EPHEMERAL {
+				// Ephemeral models are inlined into their consumers and have no asset of their own
+				continue
+			}
 			generatorsList = append(generatorsList, generators.InitGenModelSQLAsset(config, projectProfile, modelConfig))
 		case internalmodels.SOURCE:
 			generatorsList = append(generatorsList, generators.InitGenModelRawAsset(config, projectProfile, modelConfig))
+		case internalmodels.SEED:
+			generatorsList = append(generatorsList, generators.InitGenSeedAsset(config, projectProfile, modelConfig))
+		case internalmodels.EXTERNAL_SOURCE:
+			generatorsList = append(generatorsList, generators.InitGenSourceAsset(config, projectProfile, modelConfig))
 		default:
 			panic("unknown model type")
 		}
 		// generatorsList = append(generatorsList, generators.InitGenModelAsset(config, projectProfile, modelConfig))
 	}
-	priorityGroups := app.dependnacyGraph.Build(modelConfigs)
+	priorityGroups, err := app.dependnacyGraph.Build(modelConfigs)
+	if err != nil {
+		fmt.Printf("can not build the dependency graph %v\n", err)
+		return err
+	}
 	fmt.Println(priorityGroups)
 
 	for _, modelConfig := range modelConfigs {
@@ -74,10 +106,29 @@ func (app *Application) GenegateAssets(pojectPath string, configFilePath string,
 		generatorsList = append(generatorsList, generators.InitGenSQLModelTest(config, projectProfile, testConfig))
 	}
 
-	generatorsList = append(generatorsList, generators.InitGenAssetsConfig(config, projectProfile, modelConfigs, priorityGroups))
+	unitTestConfigs, err := services.InitUnitTestConfigs(config, projectProfile, modelConfigs)
+	if err != nil {
+		fmt.Printf("can not create a configuration for unit tests %v\n", err)
+		return err
+	}
+
+	for _, unitTestConfig := range unitTestConfigs {
+		generatorsList = append(generatorsList, generators.InitGenUnitTest(config, projectProfile, unitTestConfig))
+	}
+
+	executableConfigs := make([]*internalmodels.ModelConfig, 0, len(modelConfigs))
+	for _, modelConfig := range modelConfigs {
+		if modelConfig.ModelProfile == nil || modelConfig.ModelProfile.Materialization != configs.MAT_EPHEMERAL {
+			executableConfigs = append(executableConfigs, modelConfig)
+		}
+	}
+
+	generatorsList = append(generatorsList, generators.InitGenMacros(config, projectProfile))
+	generatorsList = append(generatorsList, generators.InitGenAssetsConfig(config, projectProfile, executableConfigs, priorityGroups))
 	generatorsList = append(generatorsList, generators.InitGenGraph(config, projectProfile, modelConfigs))
 	generatorsList = append(generatorsList, generators.InitGenReadme(config, projectProfile, modelConfigs))
 	generatorsList = append(generatorsList, generators.InitGenTestConfig(config, projectProfile, testConfigs))
+	generatorsList = append(generatorsList, generators.InitGenUnitTestsConfig(config, projectProfile, unitTestConfigs))
 
 	fmt.Printf("Files %d\n", len(generatorsList))
 
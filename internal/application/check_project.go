@@ -0,0 +1,74 @@
+package application
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/go-teal/teal/internal/domain/services"
+	"github.com/go-teal/teal/internal/domain/utils"
+	"github.com/go-teal/teal/pkg/configs"
+)
+
+// CheckProject checks that every raw asset of the project has exactly one executor registration in its Go sources
+func (app *Application) CheckProject(projectPath string) error {
+	projectProfile, err := app.configService.GetProfileProfile(projectPath)
+	if err != nil {
+		return err
+	}
+
+	rawAssets := make(map[string]bool)
+	for _, stage := range projectProfile.Models.Stages {
+		for _, model := range stage.Models {
+			if model.Materialization == configs.MAT_RAW {
+				_, refName := utils.CreateModelName(stage.Name, model.Name)
+				rawAssets[refName] = true
+			}
+		}
+	}
+
+	scan, err := services.ScanExecutorRegistrations(projectPath)
+	if err != nil {
+		return err
+	}
+	registrations := make(map[string][]string)
+	for _, registration := range scan.Registrations {
+		registrations[registration.AssetName] = append(registrations[registration.AssetName], registration.Position)
+	}
+
+	problems := 0
+	for _, assetName := range sortedKeys(rawAssets) {
+		if _, ok := registrations[assetName]; !ok {
+			fmt.Printf("MISSING   %s: executor is not registered\n", assetName)
+			problems++
+		}
+	}
+	for _, assetName := range sortedKeys(registrations) {
+		positions := registrations[assetName]
+		if len(positions) > 1 {
+			fmt.Printf("DUPLICATE %s: registered at %s\n", assetName, strings.Join(positions, ", "))
+			problems++
+		}
+		if !rawAssets[assetName] {
+			fmt.Printf("UNKNOWN   %s: registered at %s, but there is no raw asset with this name\n", assetName, strings.Join(positions, ", "))
+		}
+	}
+	for _, position := range scan.Dynamic {
+		fmt.Printf("DYNAMIC   %s: the asset name is not a string literal and can not be checked\n", position)
+	}
+
+	if problems > 0 {
+		return fmt.Errorf("check failed: %d problem(s) with raw asset executors", problems)
+	}
+	fmt.Printf("OK: %d raw asset(s), all executors are registered\n", len(rawAssets))
+	return nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
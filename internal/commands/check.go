@@ -0,0 +1,40 @@
+package commands
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/go-teal/teal/internal/application"
+)
+
+func NewCheckCommand(app *application.Application) *CheckCommand {
+	checkCommand := &CheckCommand{
+		fs:  flag.NewFlagSet("check", flag.ContinueOnError),
+		app: app,
+	}
+
+	checkCommand.fs.StringVar(&checkCommand.projectPath, "project-path", ".", "Project dir")
+
+	return checkCommand
+}
+
+type CheckCommand struct {
+	fs          *flag.FlagSet
+	projectPath string
+	app         *application.Application
+}
+
+func (checkCommand *CheckCommand) Name() string {
+	return checkCommand.fs.Name()
+}
+
+func (checkCommand *CheckCommand) Init(args []string) error {
+	checkCommand.projectPath = "."
+	return checkCommand.fs.Parse(args)
+}
+
+func (checkCommand *CheckCommand) Run() error {
+	fmt.Println("project-path:", checkCommand.projectPath)
+
+	return checkCommand.app.CheckProject(checkCommand.projectPath)
+}
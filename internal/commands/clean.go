@@ -19,6 +19,7 @@ func NewCleanCommand(app *application.Application) *CleanCommand {
 	cleanCommand.fs.StringVar(&cleanCommand.models, "model", "", "models for cleaning")
 	cleanCommand.fs.BoolVar(&cleanCommand.cleanMain, "clean-main", false, "delete production main.go")
 	cleanCommand.fs.BoolVar(&cleanCommand.cleanMainUI, "clean-main-ui", false, "delete UI debug main.go")
+	cleanCommand.fs.BoolVar(&cleanCommand.cleanMainUnit, "clean-main-unit", false, "delete unit tests main.go")
 	cleanCommand.fs.BoolVar(&cleanCommand.cleanDockerfile, "clean-dockerfile", false, "delete Dockerfile")
 	cleanCommand.fs.BoolVar(&cleanCommand.cleanGoMod, "clean-go-mod", false, "delete go.mod and go.sum")
 	cleanCommand.fs.BoolVar(&cleanCommand.cleanAll, "clean-all", false, "delete all generated files")
@@ -32,6 +33,7 @@ type CleanCommand struct {
 	projectPath     string
 	cleanMain       bool
 	cleanMainUI     bool
+	cleanMainUnit   bool
 	cleanDockerfile bool
 	cleanGoMod      bool
 	cleanAll        bool
@@ -71,6 +73,7 @@ func (cleanCommand *CleanCommand) Run() error {
 		cleanCommand.models = "*"
 		cleanCommand.cleanMain = true
 		cleanCommand.cleanMainUI = true
+		cleanCommand.cleanMainUnit = true
 		cleanCommand.cleanDockerfile = true
 		cleanCommand.cleanGoMod = true
 	}
@@ -79,7 +82,7 @@ func (cleanCommand *CleanCommand) Run() error {
 	cleanModels := cleanCommand.models == "*" || cleanCommand.models != ""
 
 	// Determine if any specific file flags are set
-	hasSpecificFileFlags := cleanCommand.cleanMain || cleanCommand.cleanMainUI ||
+	hasSpecificFileFlags := cleanCommand.cleanMain || cleanCommand.cleanMainUI || cleanCommand.cleanMainUnit ||
 		cleanCommand.cleanDockerfile || cleanCommand.cleanGoMod
 
 	// Clean models
@@ -122,6 +125,12 @@ func (cleanCommand *CleanCommand) Run() error {
 				fmt.Fprintf(os.Stderr, "Error cleaning model_tests: %v\n", cleanErr)
 			}
 
+			fmt.Println("Cleaning internal/unit_tests...")
+			cleanErr = os.RemoveAll(cleanCommand.projectPath + "/internal/unit_tests")
+			if cleanErr != nil {
+				fmt.Fprintf(os.Stderr, "Error cleaning unit_tests: %v\n", cleanErr)
+			}
+
 			fmt.Println("Cleaning docs/...")
 			cleanErr = os.Remove(cleanCommand.projectPath + "/docs/graph.mmd")
 			if cleanErr != nil && !os.IsNotExist(cleanErr) {
@@ -162,6 +171,20 @@ func (cleanCommand *CleanCommand) Run() error {
 		}
 	}
 
+	// Clean unit tests main.go
+	if cleanCommand.cleanMainUnit {
+		fmt.Printf("Cleaning unit tests main.go: cmd/%s-unit/%s-unit.go\n", profile.Name, profile.Name)
+		cleanErr := os.Remove(cleanCommand.projectPath + "/cmd/" + profile.Name + "-unit/" + profile.Name + "-unit.go")
+		if cleanErr != nil && !os.IsNotExist(cleanErr) {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", cleanErr)
+		}
+
+		cleanErr = os.RemoveAll(cleanCommand.projectPath + "/cmd/" + profile.Name + "-unit")
+		if cleanErr != nil && !os.IsNotExist(cleanErr) {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", cleanErr)
+		}
+	}
+
 	// Clean Dockerfile
 	if cleanCommand.cleanDockerfile {
 		fmt.Println("Cleaning Dockerfile...")
@@ -0,0 +1,69 @@
+package commands
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/go-teal/teal/internal/application"
+)
+
+func NewTestCommand(app *application.Application) *TestCommand {
+	testCommand := &TestCommand{
+		fs:  flag.NewFlagSet("test", flag.ContinueOnError),
+		app: app,
+	}
+
+	testCommand.fs.BoolVar(&testCommand.unit, "unit", false, "Run the unit tests of assets/unit_tests")
+	testCommand.fs.StringVar(&testCommand.projectPath, "project-path", ".", "Project dir")
+	testCommand.fs.StringVar(&testCommand.configFile, "config-file", defaultConfig, "Path to config.yaml")
+	testCommand.fs.StringVar(&testCommand.selectTests, "select", "", "Unit tests or models to test (optional)")
+
+	return testCommand
+}
+
+type TestCommand struct {
+	fs          *flag.FlagSet
+	unit        bool
+	projectPath string
+	configFile  string
+	selectTests string
+	app         *application.Application
+}
+
+func (testCommand *TestCommand) Name() string {
+	return testCommand.fs.Name()
+}
+
+func (testCommand *TestCommand) Init(args []string) error {
+	testCommand.projectPath = "."
+	return testCommand.fs.Parse(args)
+}
+
+// Run regenerates the project and runs its unit tests main, the data tests run with the production binary
+func (testCommand *TestCommand) Run() error {
+	if !testCommand.unit {
+		return fmt.Errorf("only unit tests are run by teal test, use --unit; the data tests run with the production binary")
+	}
+	if testCommand.configFile == defaultConfig {
+		testCommand.configFile = testCommand.projectPath + "/" + testCommand.configFile
+	}
+	profile, err := testCommand.app.GetConfigService().GetProfileProfile(testCommand.projectPath)
+	if err != nil {
+		return fmt.Errorf("failed to load profile.yaml: %w", err)
+	}
+
+	if err := testCommand.app.GenegateAssets(testCommand.projectPath, testCommand.configFile, ""); err != nil {
+		return err
+	}
+
+	cmd := exec.Command("go", "run", "./cmd/"+profile.Name+"-unit", "--select", testCommand.selectTests)
+	cmd.Dir = testCommand.projectPath
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("unit tests failed: %w", err)
+	}
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	_ "embed"
 	"os"
 	"sort"
+	"strconv"
 
 	pongo2 "github.com/flosch/pongo2/v6"
 	internalmodels "github.com/go-teal/teal/internal/domain/internal_models"
@@ -72,10 +73,24 @@ func (g *GenAssetsConfig) RenderToFile() (error, bool) {
 		sortedPriorityGroups[i] = sortedGroup
 	}
 
+	runHooksConnection := g.profile.Connection
+	if runHooksConnection == "" {
+		runHooksConnection = "default"
+	}
+
+	// The vars are embedded as a Go string literal of a JSON object
+	varsJSON, err := g.profile.VarsJSON()
+	if err != nil {
+		return err, false
+	}
+
 	output, err := templ.Execute(pongo2.Context{
-		"Config":         g.config,
-		"Assets":         sortedAssets,
-		"PriorityGroups": sortedPriorityGroups,
+		"VarsJSON":           strconv.Quote(varsJSON),
+		"Config":             g.config,
+		"Profile":            g.profile,
+		"Assets":             sortedAssets,
+		"PriorityGroups":     sortedPriorityGroups,
+		"RunHooksConnection": runHooksConnection,
 	})
 	if err != nil {
 		panic(err)
@@ -43,9 +43,14 @@ func (g *GenDockerfile) RenderToFile() (error, bool) {
 		return err, false
 	}
 
+	// Seed files are read by the binary at runtime
+	_, err = os.Stat(g.config.ProjectPath + "/assets/seeds")
+	hasSeeds := err == nil
+
 	output, err := tmpl.Execute(pongo2.Context{
 		"Config":         g.config,
 		"ProjectProfile": g.profile,
+		"HasSeeds":       hasSeeds,
 	})
 	if err != nil {
 		return err, false
@@ -56,22 +56,28 @@ func sanitizeNodeID(name string) string {
 func (g *GenGraph) RenderToFile() (error, bool) {
 
 	utils.CreateDir(g.config.ProjectPath + "/docs")
-	stages := make([]string, len(g.profile.Models.Stages))
-	for i, stage := range g.profile.Models.Stages {
-		stages[i] = stage.Name
+	stages := make([]string, 0, len(g.profile.Models.Stages)+len(g.profile.Sources))
+	for _, stage := range g.profile.Models.Stages {
+		stages = append(stages, stage.Name)
+	}
+	// Every source is drawn as a subgraph of its tables
+	for _, source := range g.profile.Sources {
+		stages = append(stages, source.Name)
 	}
 
 	// Create graph nodes with sanitized IDs
 	nodes := make([]*GraphNode, len(g.modelsConfigs))
 	for i, model := range g.modelsConfigs {
-		downstreamIDs := make([]string, len(model.Downstreams))
-		for j, ds := range model.Downstreams {
+		downstreamIDs := make([]string, len(model.LineageDownstreams))
+		for j, ds := range model.LineageDownstreams {
 			downstreamIDs[j] = sanitizeNodeID(ds)
 		}
 
 		materialization := ""
 		if model.ModelProfile != nil {
 			materialization = string(model.ModelProfile.Materialization)
+		} else if model.ModelType == internalmodels.EXTERNAL_SOURCE {
+			materialization = "source"
 		}
 
 		nodes[i] = &GraphNode{
@@ -79,7 +85,7 @@ func (g *GenGraph) RenderToFile() (error, bool) {
 			ModelName:       model.ModelName,
 			Stage:           model.Stage,
 			Materialization: materialization,
-			Downstreams:     model.Downstreams,
+			Downstreams:     model.LineageDownstreams,
 			DownstreamIDs:   downstreamIDs,
 		}
 	}
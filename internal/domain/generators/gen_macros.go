@@ -0,0 +1,73 @@
+package generators
+
+import (
+	_ "embed"
+	"os"
+	"strconv"
+
+	pongo2 "github.com/flosch/pongo2/v6"
+	"github.com/go-teal/teal/internal/domain/utils"
+	"github.com/go-teal/teal/pkg/configs"
+)
+
+//go:embed templates/macros.go.tmpl
+var macrosTemplate string
+
+const GO_MACROS_FILE_NAME = "macros.go"
+
+type GenMacros struct {
+	config  *configs.Config
+	profile *configs.ProjectProfile
+}
+
+func InitGenMacros(config *configs.Config, profile *configs.ProjectProfile) Generator {
+	return &GenMacros{
+		config:  config,
+		profile: profile,
+	}
+}
+
+// GetFileName implements Generator.
+func (g *GenMacros) GetFileName() string {
+	return GO_MACROS_FILE_NAME
+}
+
+// GetFullPath implements Generator.
+func (g *GenMacros) GetFullPath() string {
+	return g.config.ProjectPath + "/internal/assets/" + GO_MACROS_FILE_NAME
+}
+
+// RenderToFile implements Generator. The file is written without macros too, so that removed macros disappear.
+func (g *GenMacros) RenderToFile() (error, bool) {
+	utils.CreateDir(g.config.ProjectPath + "/internal/assets/")
+
+	// The sources are embedded as Go string literals
+	macroFiles := make([]*configs.MacroFile, 0, len(g.profile.Macros))
+	for _, macroFile := range g.profile.Macros {
+		macroFiles = append(macroFiles, &configs.MacroFile{
+			FileName: macroFile.FileName,
+			Source:   strconv.Quote(macroFile.Source),
+			Names:    macroFile.Names,
+		})
+	}
+
+	templ, err := pongo2.FromString(macrosTemplate)
+	if err != nil {
+		return err, false
+	}
+	output, err := templ.Execute(pongo2.Context{
+		"MacroFiles": macroFiles,
+	})
+	if err != nil {
+		return err, false
+	}
+
+	file, err := os.Create(g.GetFullPath())
+	if err != nil {
+		panic(err)
+	}
+	defer file.Close()
+
+	_, err = file.WriteString(output)
+	return err, false
+}
@@ -0,0 +1,81 @@
+package generators
+
+import (
+	_ "embed"
+	"os"
+
+	pongo2 "github.com/flosch/pongo2/v6"
+	"github.com/go-teal/teal/internal/domain/utils"
+	"github.com/go-teal/teal/pkg/configs"
+)
+
+//go:embed templates/main_unit.go.tmpl
+var mainUnitTemplate string
+
+type GenMainUnit struct {
+	config  *configs.Config
+	profile *configs.ProjectProfile
+}
+
+// GetFileName implements Generator.
+func (g *GenMainUnit) GetFileName() string {
+	return g.profile.Name + "-unit.go"
+}
+
+// GetFullPath implements Generator.
+func (g *GenMainUnit) GetFullPath() string {
+	return g.config.ProjectPath + "/cmd/" + g.profile.Name + "-unit/" + g.GetFileName()
+}
+
+func InitGenMainUnit(config *configs.Config, profile *configs.ProjectProfile) Generator {
+	return &GenMainUnit{
+		config:  config,
+		profile: profile,
+	}
+}
+
+func (g *GenMainUnit) RenderToFile() (error, bool) {
+	mainDirName := g.config.ProjectPath + "/cmd/" + g.profile.Name + "-unit"
+	if g.config.ProjectPath == "." {
+		mainDirName = "cmd/" + g.profile.Name + "-unit"
+	}
+	utils.CreateDir(mainDirName)
+
+	templ, err := pongo2.FromString(mainUnitTemplate)
+	if err != nil {
+		panic(err)
+	}
+
+	_, err = os.Stat(g.GetFullPath())
+
+	if !os.IsNotExist(err) {
+		return nil, true
+	}
+
+	connectionsFlags := make(map[string]bool)
+
+	for _, c := range g.config.Connections {
+		connectionsFlags[c.Type] = true
+	}
+
+	output, err := templ.Execute(pongo2.Context{
+		"Profile":     g.profile,
+		"Config":      g.config,
+		"Connections": connectionsFlags,
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	file, err := os.Create(g.GetFullPath())
+
+	if err != nil {
+		panic(err)
+	}
+
+	defer file.Close()
+
+	_, err = file.WriteString(output)
+
+	return err, false
+}
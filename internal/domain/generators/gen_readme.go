@@ -2,7 +2,9 @@ package generators
 
 import (
 	_ "embed"
+	"encoding/json"
 	"os"
+	"sort"
 
 	pongo2 "github.com/flosch/pongo2/v6"
 	internalmodels "github.com/go-teal/teal/internal/domain/internal_models"
@@ -54,15 +56,41 @@ func (g *GenReadme) RenderToFile() (error, bool) {
 	// Separate SQL and raw assets
 	sqlAssets := []*internalmodels.ModelConfig{}
 	rawAssets := []*internalmodels.ModelConfig{}
+	sourceAssets := []*internalmodels.ModelConfig{}
 
 	for _, asset := range g.modelsConfigs {
 		if asset.ModelType == internalmodels.DATABASE {
 			sqlAssets = append(sqlAssets, asset)
 		} else if asset.ModelType == internalmodels.SOURCE {
 			rawAssets = append(rawAssets, asset)
+		} else if asset.ModelType == internalmodels.EXTERNAL_SOURCE {
+			sourceAssets = append(sourceAssets, asset)
 		}
 	}
 
+	// Vars with their defaults as JSON, sorted by name
+	varsJSON, err := g.profile.VarsJSON()
+	if err != nil {
+		return err, false
+	}
+	var varDefaults map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(varsJSON), &varDefaults); err != nil {
+		return err, false
+	}
+	vars := make([]struct {
+		Name    string
+		Default string
+	}, 0, len(varDefaults))
+	for name, value := range varDefaults {
+		vars = append(vars, struct {
+			Name    string
+			Default string
+		}{name, string(value)})
+	}
+	sort.Slice(vars, func(i, j int) bool {
+		return vars[i].Name < vars[j].Name
+	})
+
 	templ, err := pongo2.FromString(readmeTemplate)
 	if err != nil {
 		return err, false
@@ -75,6 +103,8 @@ func (g *GenReadme) RenderToFile() (error, bool) {
 		"Stages":      stages,
 		"Assets":      g.modelsConfigs,
 		"RawAssets":   rawAssets,
+		"Sources":     sourceAssets,
+		"Vars":        vars,
 		"Connections": g.config.Connections,
 	})
 	if err != nil {
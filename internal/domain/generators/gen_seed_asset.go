@@ -0,0 +1,85 @@
+package generators
+
+import (
+	_ "embed"
+	"encoding/base64"
+	"os"
+
+	pongo2 "github.com/flosch/pongo2/v6"
+	internalmodels "github.com/go-teal/teal/internal/domain/internal_models"
+	"github.com/go-teal/teal/internal/domain/utils"
+	"github.com/go-teal/teal/pkg/configs"
+)
+
+//go:embed templates/dwh_seed_asset.tmpl
+var dwhSeedTemplate string
+
+type GenSeedAsset struct {
+	config         *configs.Config
+	projectProfile *configs.ProjectProfile
+	modelConfig    *internalmodels.ModelConfig
+}
+
+func InitGenSeedAsset(
+	config *configs.Config,
+	projectProfile *configs.ProjectProfile,
+	modelConfig *internalmodels.ModelConfig,
+
+) Generator {
+	return &GenSeedAsset{
+		config:         config,
+		projectProfile: projectProfile,
+		modelConfig:    modelConfig,
+	}
+}
+
+// GetFileName implements Generator.
+func (g *GenSeedAsset) GetFileName() string {
+	return g.modelConfig.ModelName
+}
+
+// GetFullPath implements Generator.
+func (g *GenSeedAsset) GetFullPath() string {
+	return g.config.ProjectPath + "/internal/assets/" + g.GetFileName() + ".go"
+}
+
+func (g *GenSeedAsset) RenderToFile() (error, bool) {
+
+	dirName := g.config.ProjectPath + "/internal/assets/"
+	utils.CreateDir(dirName)
+
+	// Base64 encode the description to avoid issues with special characters in templates
+	if g.modelConfig.ModelProfile != nil && g.modelConfig.ModelProfile.Description != "" {
+		encoded := base64.StdEncoding.EncodeToString([]byte(g.modelConfig.ModelProfile.Description))
+		g.modelConfig.ModelProfile.Description = encoded
+	}
+
+	goTempl, err := pongo2.FromString(dwhSeedTemplate)
+	if err != nil {
+		return err, false
+	}
+
+	output, err := goTempl.Execute(pongo2.Context{
+		"ModelName":    g.modelConfig.ModelName,
+		"GoName":       g.modelConfig.GoName,
+		"ModelProfile": g.modelConfig.ModelProfile,
+		"Upstreams":    g.modelConfig.Upstreams,
+		"Downstreams":  g.modelConfig.Downstreams,
+		"FilePath":     g.modelConfig.SeedFilePath,
+		"Format":       g.modelConfig.SeedFormat,
+	})
+	if err != nil {
+		return err, false
+	}
+
+	file, err := os.Create(g.GetFullPath())
+
+	if err != nil {
+		panic(err)
+	}
+
+	defer file.Close()
+
+	_, err = file.WriteString(output)
+	return err, false
+}
@@ -0,0 +1,83 @@
+package generators
+
+import (
+	_ "embed"
+	"encoding/base64"
+	"os"
+
+	pongo2 "github.com/flosch/pongo2/v6"
+	internalmodels "github.com/go-teal/teal/internal/domain/internal_models"
+	"github.com/go-teal/teal/internal/domain/utils"
+	"github.com/go-teal/teal/pkg/configs"
+)
+
+//go:embed templates/dwh_source_asset.tmpl
+var dwhSourceTemplate string
+
+type GenSourceAsset struct {
+	config         *configs.Config
+	projectProfile *configs.ProjectProfile
+	modelConfig    *internalmodels.ModelConfig
+}
+
+func InitGenSourceAsset(
+	config *configs.Config,
+	projectProfile *configs.ProjectProfile,
+	modelConfig *internalmodels.ModelConfig,
+
+) Generator {
+	return &GenSourceAsset{
+		config:         config,
+		projectProfile: projectProfile,
+		modelConfig:    modelConfig,
+	}
+}
+
+// GetFileName implements Generator.
+func (g *GenSourceAsset) GetFileName() string {
+	return g.modelConfig.ModelName
+}
+
+// GetFullPath implements Generator.
+func (g *GenSourceAsset) GetFullPath() string {
+	return g.config.ProjectPath + "/internal/assets/" + g.GetFileName() + ".go"
+}
+
+func (g *GenSourceAsset) RenderToFile() (error, bool) {
+
+	dirName := g.config.ProjectPath + "/internal/assets/"
+	utils.CreateDir(dirName)
+
+	// Base64 encode the description to avoid issues with special characters in templates
+	description := ""
+	if g.modelConfig.SourceProfile.Description != "" {
+		description = base64.StdEncoding.EncodeToString([]byte(g.modelConfig.SourceProfile.Description))
+	}
+
+	goTempl, err := pongo2.FromString(dwhSourceTemplate)
+	if err != nil {
+		return err, false
+	}
+
+	output, err := goTempl.Execute(pongo2.Context{
+		"ModelName":     g.modelConfig.ModelName,
+		"GoName":        g.modelConfig.GoName,
+		"SourceProfile": g.modelConfig.SourceProfile,
+		"Description":   description,
+		"Downstreams":   g.modelConfig.Downstreams,
+	})
+	if err != nil {
+		return err, false
+	}
+
+	file, err := os.Create(g.GetFullPath())
+
+	if err != nil {
+		panic(err)
+	}
+
+	defer file.Close()
+
+	_, err = file.WriteString(output)
+	return err, false
+}
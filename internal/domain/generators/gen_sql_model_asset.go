@@ -4,6 +4,7 @@ import (
 	_ "embed"
 	"encoding/base64"
 	"os"
+	"strconv"
 
 	pongo2 "github.com/flosch/pongo2/v6"
 	internalmodels "github.com/go-teal/teal/internal/domain/internal_models"
@@ -67,16 +68,37 @@ func (g *GenSQLModelAsset) RenderToFile() (error, bool) {
 		materialization = string(g.modelConfig.ModelProfile.Materialization)
 	}
 
+	// REFRESH MATERIALIZED VIEW CONCURRENTLY requires a unique index on the view
+	refreshConcurrently := g.modelConfig.PrimaryKeyExpression != ""
+	for _, index := range g.modelConfig.Indexes {
+		refreshConcurrently = refreshConcurrently || index.Unique
+	}
+
+	// Column descriptions are Go string literals
+	var columns []*configs.ColumnProfile
+	if g.modelConfig.ModelProfile != nil {
+		for _, column := range g.modelConfig.ModelProfile.Columns {
+			quoted := *column
+			if column.Description != "" {
+				quoted.Description = strconv.Quote(column.Description)
+			}
+			columns = append(columns, &quoted)
+		}
+	}
+
 	output, err := goTempl.Execute(pongo2.Context{
 		"ModelName":            g.modelConfig.ModelName,
 		"GoName":               g.modelConfig.GoName,
 		"NameUpperCase":        g.modelConfig.NameUpperCase,
 		"SqlByteBuffer":        g.modelConfig.SqlByteBuffer.String(),
+		"ContractSQL":          g.modelConfig.ContractSQL,
+		"Columns":              columns,
 		"ModelFieldsFunc":      g.modelConfig.ModelFieldsFunc,
 		"ModelProfile":         g.modelConfig.ModelProfile,
 		"Materialization":      materialization,
 		"PrimaryKeyExpression": g.modelConfig.PrimaryKeyExpression,
 		"Indexes":              g.modelConfig.Indexes,
+		"RefreshConcurrently":  refreshConcurrently,
 		"Upstreams":            g.modelConfig.Upstreams,
 		"Downstreams":          g.modelConfig.Downstreams,
 	})
@@ -0,0 +1,62 @@
+package generators
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	internalmodels "github.com/go-teal/teal/internal/domain/internal_models"
+	"github.com/go-teal/teal/pkg/configs"
+)
+
+// renderSQLModelAsset generates the asset of a model selecting from staging.orders and returns the Go source
+func renderSQLModelAsset(t *testing.T, materialization configs.MatType) string {
+	t.Helper()
+	dir := t.TempDir()
+	modelConfig := &internalmodels.ModelConfig{
+		GoName:        "DdsOrders",
+		ModelName:     "dds.orders",
+		Stage:         "dds",
+		NameUpperCase: "DDS_ORDERS",
+		ModelProfile: &configs.ModelProfile{
+			Name:            "orders",
+			Stage:           "dds",
+			Connection:      "default",
+			Materialization: materialization,
+		},
+	}
+	modelConfig.SqlByteBuffer.WriteString("select id, name from staging.orders")
+	g := InitGenModelSQLAsset(&configs.Config{ProjectPath: dir}, &configs.ProjectProfile{}, modelConfig)
+	if err, _ := g.RenderToFile(); err != nil {
+		t.Fatalf("RenderToFile: %v", err)
+	}
+	output, err := os.ReadFile(g.GetFullPath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(output)
+}
+
+func TestGenSQLModelAssetReplacesFromShadowByColumnNames(t *testing.T) {
+	output := renderSQLModelAsset(t, configs.MAT_TABLE)
+	want := "insert into dds.orders ({{ ModelFields }}) (select {{ ModelFields }} from dds.orders__shadow);"
+	if !strings.Contains(output, want) {
+		t.Fatalf("the replace from shadow SQL does not list the columns, want %q in:\n%s", want, output)
+	}
+}
+
+func TestGenSQLModelAssetMaterializedView(t *testing.T) {
+	output := renderSQLModelAsset(t, configs.MAT_MATERIALIZED_VIEW)
+	for _, want := range []string{
+		"const SQL_DDS_ORDERS_CREATE_MATERIALIZED_VIEW = `\ncreate materialized view dds.orders\nas (select id, name from staging.orders);",
+		"const SQL_DDS_ORDERS_REFRESH_MATERIALIZED_VIEW = `\nrefresh materialized view dds.orders\n`",
+		"const SQL_DDS_ORDERS_DROP_MATERIALIZED_VIEW = `\ndrop materialized view dds.orders\n`",
+		"CreateMaterializedViewSQL: \tSQL_DDS_ORDERS_CREATE_MATERIALIZED_VIEW,",
+		"RefreshMaterializedViewSQL: SQL_DDS_ORDERS_REFRESH_MATERIALIZED_VIEW,",
+		"DropMaterializedViewSQL: \tSQL_DDS_ORDERS_DROP_MATERIALIZED_VIEW,",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("want %q in:\n%s", want, output)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package generators
+
+import (
+	_ "embed"
+	"os"
+	"strconv"
+
+	pongo2 "github.com/flosch/pongo2/v6"
+	internalmodels "github.com/go-teal/teal/internal/domain/internal_models"
+	"github.com/go-teal/teal/internal/domain/utils"
+	"github.com/go-teal/teal/pkg/configs"
+)
+
+//go:embed templates/unit_test.go.tmpl
+var unitTestTemplate string
+
+//go:embed templates/unit_tests_config.go.tmpl
+var unitTestsConfigTemplate string
+
+const UNIT_TESTS_DIR = "/internal/unit_tests/"
+
+type GenUnitTest struct {
+	config         *configs.Config
+	projectProfile *configs.ProjectProfile
+	unitTestConfig *internalmodels.UnitTestConfig
+}
+
+// GetFileName implements Generator.
+func (g *GenUnitTest) GetFileName() string {
+	return g.unitTestConfig.TestName
+}
+
+// GetFullPath implements Generator.
+func (g *GenUnitTest) GetFullPath() string {
+	return g.config.ProjectPath + UNIT_TESTS_DIR + g.GetFileName() + ".go"
+}
+
+// RenderToFile implements Generator.
+func (g *GenUnitTest) RenderToFile() (error, bool) {
+	utils.CreateDir(g.config.ProjectPath + UNIT_TESTS_DIR)
+
+	goTempl, err := pongo2.FromString(unitTestTemplate)
+	if err != nil {
+		return err, false
+	}
+
+	// SQL and descriptions are rendered as Go string literals, they may contain any characters
+	fixtures := make([]map[string]string, len(g.unitTestConfig.Fixtures))
+	for i, fixture := range g.unitTestConfig.Fixtures {
+		fixtures[i] = map[string]string{
+			"Name":     fixture.Name,
+			"Relation": fixture.Relation,
+			"SQL":      strconv.Quote(fixture.SQL),
+		}
+	}
+	expectedColumns := make([]string, len(g.unitTestConfig.ExpectedColumns))
+	for i, column := range g.unitTestConfig.ExpectedColumns {
+		expectedColumns[i] = strconv.Quote(column)
+	}
+	varsJSON := ""
+	if g.unitTestConfig.VarsJSON != "" {
+		varsJSON = strconv.Quote(g.unitTestConfig.VarsJSON)
+	}
+
+	output, err := goTempl.Execute(pongo2.Context{
+		"TestName":        g.unitTestConfig.TestName,
+		"GoName":          g.unitTestConfig.GoName,
+		"ModelName":       g.unitTestConfig.ModelName,
+		"Description":     strconv.Quote(g.unitTestConfig.Profile.Description),
+		"Fixtures":        fixtures,
+		"ExpectedSQL":     strconv.Quote(g.unitTestConfig.ExpectedSQL),
+		"ExpectedColumns": expectedColumns,
+		"VarsJSON":        varsJSON,
+		"RunDate":         g.unitTestConfig.Profile.RunDate,
+	})
+	if err != nil {
+		return err, false
+	}
+
+	file, err := os.Create(g.GetFullPath())
+	if err != nil {
+		panic(err)
+	}
+	defer file.Close()
+
+	_, err = file.WriteString(output)
+	return err, false
+}
+
+func InitGenUnitTest(
+	config *configs.Config,
+	projectProfile *configs.ProjectProfile,
+	unitTestConfig *internalmodels.UnitTestConfig,
+) Generator {
+	return &GenUnitTest{
+		config:         config,
+		projectProfile: projectProfile,
+		unitTestConfig: unitTestConfig,
+	}
+}
+
+type GenUnitTestsConfig struct {
+	config          *configs.Config
+	profile         *configs.ProjectProfile
+	unitTestConfigs []*internalmodels.UnitTestConfig
+}
+
+// GetFileName implements Generator.
+func (g *GenUnitTestsConfig) GetFileName() string {
+	return GO_ASSETS_CONFIG_FILE_NAME
+}
+
+// GetFullPath implements Generator.
+func (g *GenUnitTestsConfig) GetFullPath() string {
+	return g.config.ProjectPath + UNIT_TESTS_DIR + GO_ASSETS_CONFIG_FILE_NAME
+}
+
+// InitGenUnitTestsConfig generates the map of the unit tests, it is generated without unit tests too
+func InitGenUnitTestsConfig(
+	config *configs.Config,
+	profile *configs.ProjectProfile,
+	unitTestConfigs []*internalmodels.UnitTestConfig,
+) Generator {
+	return &GenUnitTestsConfig{
+		config:          config,
+		profile:         profile,
+		unitTestConfigs: unitTestConfigs,
+	}
+}
+
+func (g *GenUnitTestsConfig) RenderToFile() (error, bool) {
+	utils.CreateDir(g.config.ProjectPath + UNIT_TESTS_DIR)
+	templ, err := pongo2.FromString(unitTestsConfigTemplate)
+	if err != nil {
+		panic(err)
+	}
+
+	output, err := templ.Execute(pongo2.Context{
+		"Config": g.config,
+		"Tests":  g.unitTestConfigs,
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	file, err := os.Create(g.GetFullPath())
+	if err != nil {
+		panic(err)
+	}
+	defer file.Close()
+
+	_, err = file.WriteString(output)
+	return err, false
+}
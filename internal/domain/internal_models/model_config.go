@@ -12,6 +12,9 @@ const (
 	DATABASE ModelType = iota
 	SOURCE
 	CUSTOM
+	SEED
+	// EXTERNAL_SOURCE is a table of profile.yaml sources, loaded outside of the project
+	EXTERNAL_SOURCE
 )
 
 type ModelConfig struct {
@@ -20,17 +23,27 @@ type ModelConfig struct {
 	Stage         string
 	NameUpperCase string
 	SqlByteBuffer bytes.Buffer
-	Config        *configs.Config
-	Profile       *configs.ProjectProfile
-	Upstreams     []string
-	Downstreams   []string
-	Priority      int
-	ModelProfile  *configs.ModelProfile
-	// SourceProfile        *configs.SourceProfile
+	// ContractSQL is the model query before the casts of enforce_types, the contract is checked against it
+	ContractSQL string
+	Config      *configs.Config
+	Profile     *configs.ProjectProfile
+	Upstreams   []string
+	Downstreams []string
+	// LineageUpstreams and LineageDownstreams include ephemeral models,
+	// which are inlined as CTEs and therefore missing in Upstreams and Downstreams
+	LineageUpstreams   []string
+	LineageDownstreams []string
+	Priority           int
+	ModelProfile       *configs.ModelProfile
+	// SourceProfile is set for EXTERNAL_SOURCE only, which has no ModelProfile
+	SourceProfile        *configs.SourceTableProfile
 	ModelType            ModelType
 	ModelFieldsFunc      string
 	PrimaryKeyExpression string
 	Indexes              []*IndexConfig
+	// SeedFilePath is relative to the project directory
+	SeedFilePath string
+	SeedFormat   string
 }
 
 type IndexConfig struct {
@@ -0,0 +1,18 @@
+package internalmodels
+
+import (
+	"github.com/go-teal/teal/pkg/configs"
+	"github.com/go-teal/teal/pkg/models"
+)
+
+// UnitTestConfig is a unit test of assets/unit_tests with the SQL of its fixtures and of the expected rows
+type UnitTestConfig struct {
+	GoName          string
+	TestName        string
+	ModelName       string
+	Profile         *configs.UnitTestProfile
+	Fixtures        []*models.UnitTestFixture
+	ExpectedSQL     string
+	ExpectedColumns []string
+	VarsJSON        string
+}
@@ -5,6 +5,7 @@ import (
 	"sort"
 
 	internalmodels "github.com/go-teal/teal/internal/domain/internal_models"
+	"github.com/go-teal/teal/pkg/configs"
 )
 
 type DependnacyGraph struct {
@@ -19,21 +20,52 @@ func InitDependnacyGraph() *DependnacyGraph {
 	}
 }
 
-func (dg *DependnacyGraph) Build(modelsConfigs []*internalmodels.ModelConfig) [][]string {
+// Build resolves the execution upstreams and downstreams of the models and groups them by priority.
+// Ephemeral models are kept in the lineage only, they are executed as a part of their consumers.
+func (dg *DependnacyGraph) Build(modelsConfigs []*internalmodels.ModelConfig) ([][]string, error) {
 	modelConfigMap := make(map[string]*internalmodels.ModelConfig, len(modelsConfigs))
 	priorityMap := make(map[string]int, len(modelsConfigs))
 	for _, modelConfig := range modelsConfigs {
 		modelConfigMap[modelConfig.ModelName] = modelConfig
-		priorityMap[modelConfig.ModelName] = -1
+		modelConfig.LineageUpstreams = modelConfig.Upstreams
+		if !isEphemeral(modelConfig) {
+			priorityMap[modelConfig.ModelName] = -1
+		}
+	}
+
+	// Raw models run Go code and have no SQL to inline an ephemeral model into
+	for _, modelConfig := range modelsConfigs {
+		if modelConfig.ModelType != internalmodels.SOURCE {
+			continue
+		}
+		for _, upstreamName := range modelConfig.Upstreams {
+			if upstream, ok := modelConfigMap[upstreamName]; ok && isEphemeral(upstream) {
+				return nil, fmt.Errorf("raw model %s can not depend on ephemeral model %s, materialize %s as a view or a table",
+					modelConfig.ModelName, upstreamName, upstreamName)
+			}
+		}
+	}
+
+	for _, modelConfig := range modelsConfigs {
+		modelConfig.Upstreams = resolveExecutionUpstreams(modelConfig, modelConfigMap)
 	}
 
 	for ref, modelConfig := range modelConfigMap {
+		for _, upstreamName := range modelConfig.LineageUpstreams {
+			modelConfigMap[upstreamName].LineageDownstreams = append(modelConfigMap[upstreamName].LineageDownstreams, ref)
+		}
+		if isEphemeral(modelConfig) {
+			continue
+		}
 		for _, upstreamName := range modelConfig.Upstreams {
 			modelConfigMap[upstreamName].Downstreams = append(modelConfigMap[upstreamName].Downstreams, ref)
 		}
 	}
 	maxPriority := 0
 	for _, modelConfig := range modelsConfigs {
+		if isEphemeral(modelConfig) {
+			continue
+		}
 		propagatePriotiry(modelConfig.ModelName, 0, modelConfigMap, priorityMap, &maxPriority)
 	}
 	fmt.Println("maxPriority:", maxPriority)
@@ -44,8 +76,38 @@ func (dg *DependnacyGraph) Build(modelsConfigs []*internalmodels.ModelConfig) []
 
 	for _, modelConfig := range modelsConfigs {
 		sort.Strings(modelConfig.Downstreams)
+		sort.Strings(modelConfig.LineageDownstreams)
+	}
+	return priorityGroups, nil
+}
+
+func isEphemeral(modelConfig *internalmodels.ModelConfig) bool {
+	return modelConfig.ModelProfile != nil && modelConfig.ModelProfile.Materialization == configs.MAT_EPHEMERAL
+}
+
+// resolveExecutionUpstreams replaces ephemeral upstreams with their own upstreams,
+// since an ephemeral model is executed as a part of its consumer
+func resolveExecutionUpstreams(
+	modelConfig *internalmodels.ModelConfig,
+	modelConfigs map[string]*internalmodels.ModelConfig) []string {
+	var upstreams []string
+	visited := make(map[string]bool)
+	var visit func(names []string)
+	visit = func(names []string) {
+		for _, name := range names {
+			if visited[name] {
+				continue
+			}
+			visited[name] = true
+			if upstream, ok := modelConfigs[name]; ok && isEphemeral(upstream) {
+				visit(upstream.LineageUpstreams)
+				continue
+			}
+			upstreams = append(upstreams, name)
+		}
 	}
-	return priorityGroups
+	visit(modelConfig.LineageUpstreams)
+	return upstreams
 }
 
 func propagatePriotiry(
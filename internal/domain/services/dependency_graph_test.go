@@ -0,0 +1,44 @@
+package services
+
+import (
+	"testing"
+
+	internalmodels "github.com/go-teal/teal/internal/domain/internal_models"
+	"github.com/go-teal/teal/pkg/configs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func graphModel(name string, modelType internalmodels.ModelType, materialization configs.MatType, upstreams ...string) *internalmodels.ModelConfig {
+	return &internalmodels.ModelConfig{
+		ModelName:    name,
+		ModelType:    modelType,
+		Upstreams:    upstreams,
+		ModelProfile: &configs.ModelProfile{Materialization: materialization},
+	}
+}
+
+func TestBuildRemovesEphemeralModelsFromTheDAG(t *testing.T) {
+	orders := graphModel("staging.orders", internalmodels.DATABASE, configs.MAT_TABLE)
+	cleaned := graphModel("staging.cleaned", internalmodels.DATABASE, configs.MAT_EPHEMERAL, "staging.orders")
+	deduped := graphModel("staging.deduped", internalmodels.DATABASE, configs.MAT_EPHEMERAL, "staging.cleaned")
+	report := graphModel("mart.report", internalmodels.DATABASE, configs.MAT_TABLE, "staging.deduped", "staging.orders")
+
+	priorityGroups, err := InitDependnacyGraph().Build([]*internalmodels.ModelConfig{orders, cleaned, deduped, report})
+	require.NoError(t, err)
+
+	assert.Equal(t, [][]string{{"staging.orders"}, {"mart.report"}}, priorityGroups)
+	assert.Equal(t, []string{"staging.orders"}, report.Upstreams)
+	assert.Equal(t, []string{"staging.deduped", "staging.orders"}, report.LineageUpstreams)
+	assert.Equal(t, []string{"mart.report"}, orders.Downstreams)
+	assert.Equal(t, []string{"mart.report", "staging.cleaned"}, orders.LineageDownstreams)
+	assert.Empty(t, deduped.Downstreams)
+}
+
+func TestBuildRejectsRawModelsDependingOnEphemeralModels(t *testing.T) {
+	orders := graphModel("staging.orders", internalmodels.DATABASE, configs.MAT_EPHEMERAL)
+	export := graphModel("mart.export", internalmodels.SOURCE, configs.MAT_RAW, "staging.orders")
+
+	_, err := InitDependnacyGraph().Build([]*internalmodels.ModelConfig{orders, export})
+	assert.ErrorContains(t, err, "raw model mart.export can not depend on ephemeral model staging.orders")
+}
@@ -0,0 +1,101 @@
+package services
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ExecutorRegistration is a registration of a raw asset executor found in the Go sources of the project
+type ExecutorRegistration struct {
+	AssetName string
+	Position  string
+}
+
+// ExecutorsScan is the result of ScanExecutorRegistrations
+type ExecutorsScan struct {
+	Registrations []ExecutorRegistration
+	// Dynamic are the positions of registrations with non-literal asset names, they can not be checked
+	Dynamic []string
+}
+
+// generated directories are not scanned, they never register executors
+var skippedSourceDirs = map[string]bool{
+	"vendor":               true,
+	"node_modules":         true,
+	"internal/assets":      true,
+	"internal/model_tests": true,
+}
+
+// ScanExecutorRegistrations finds the registrations of raw asset executors in the Go files of the project:
+// calls of processing.RegisterExecutor/MustRegisterExecutor and assignments to processing.GetExecutors().Execurots[...]
+func ScanExecutorRegistrations(projectPath string) (*ExecutorsScan, error) {
+	scan := &ExecutorsScan{}
+	fileSet := token.NewFileSet()
+	err := filepath.WalkDir(projectPath, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		relPath, _ := filepath.Rel(projectPath, path)
+		if entry.IsDir() {
+			if relPath != "." && (strings.HasPrefix(entry.Name(), ".") || skippedSourceDirs[filepath.ToSlash(relPath)]) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+			return nil
+		}
+		source, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		file, err := parser.ParseFile(fileSet, relPath, source, 0)
+		if err != nil {
+			return err
+		}
+		ast.Inspect(file, func(node ast.Node) bool {
+			switch n := node.(type) {
+			case *ast.CallExpr:
+				if name := selectorName(n.Fun); (name == "RegisterExecutor" || name == "MustRegisterExecutor") && len(n.Args) > 0 {
+					scan.add(fileSet, n.Args[0])
+				}
+			case *ast.AssignStmt:
+				for _, lhs := range n.Lhs {
+					if index, ok := lhs.(*ast.IndexExpr); ok && selectorName(index.X) == "Execurots" {
+						scan.add(fileSet, index.Index)
+					}
+				}
+			}
+			return true
+		})
+		return nil
+	})
+	return scan, err
+}
+
+func (scan *ExecutorsScan) add(fileSet *token.FileSet, nameExpr ast.Expr) {
+	position := fileSet.Position(nameExpr.Pos()).String()
+	if literal, ok := nameExpr.(*ast.BasicLit); ok && literal.Kind == token.STRING {
+		if assetName, err := strconv.Unquote(literal.Value); err == nil {
+			scan.Registrations = append(scan.Registrations, ExecutorRegistration{AssetName: assetName, Position: position})
+			return
+		}
+	}
+	scan.Dynamic = append(scan.Dynamic, position)
+}
+
+func selectorName(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.SelectorExpr:
+		return e.Sel.Name
+	case *ast.Ident:
+		return e.Name
+	}
+	return ""
+}
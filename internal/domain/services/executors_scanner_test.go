@@ -0,0 +1,47 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScanExecutorRegistrations(t *testing.T) {
+	projectPath := t.TempDir()
+	writeFile := func(relPath string, content string) {
+		path := filepath.Join(projectPath, relPath)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		assert.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+	writeFile("cmd/app/main.go", `package main
+
+import "github.com/go-teal/teal/pkg/processing"
+
+func main() {
+	processing.MustRegisterExecutor("staging.load", load)
+	processing.GetExecutors().Execurots["staging.export"] = export
+	name := "staging.dynamic"
+	processing.RegisterExecutor(name, load)
+}
+`)
+	// Generated sources are skipped
+	writeFile("internal/assets/configs.go", `package assets
+
+func init() {
+	processing.MustRegisterExecutor("staging.generated", nil)
+}
+`)
+
+	scan, err := ScanExecutorRegistrations(projectPath)
+
+	assert.NoError(t, err)
+	names := []string{}
+	for _, registration := range scan.Registrations {
+		names = append(names, registration.AssetName)
+	}
+	assert.Equal(t, []string{"staging.load", "staging.export"}, names)
+	assert.Equal(t, "cmd/app/main.go:6:34", scan.Registrations[0].Position)
+	assert.Len(t, scan.Dynamic, 1)
+}
@@ -0,0 +1,55 @@
+package services
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+
+	"github.com/go-teal/teal/pkg/configs"
+	"github.com/go-teal/teal/pkg/processing"
+)
+
+const MACROS_DIR = "assets/macros"
+
+// macroDependencyPattern matches the functions resolved by the static pass, they are not available in macros
+var macroDependencyPattern = regexp.MustCompile(`\b(Ref|Source)\s*\(`)
+
+// InitMacros loads the macro files of assets/macros, the directory is optional.
+// It must be called before CombineProfiles.
+func InitMacros(config *configs.Config, projectProfile *configs.ProjectProfile) {
+	fileNames, err := filepath.Glob(filepath.Join(config.ProjectPath, MACROS_DIR, "*.sql"))
+	if err != nil {
+		panic(err)
+	}
+	sort.Strings(fileNames)
+
+	declaredIn := make(map[string]string)
+	for _, fileName := range fileNames {
+		source, err := os.ReadFile(fileName)
+		if err != nil {
+			panic(err)
+		}
+		macroFileName := MACROS_DIR + "/" + filepath.Base(fileName)
+		names, err := processing.ParseMacroFile(macroFileName, string(source))
+		if err != nil {
+			panic(err)
+		}
+		if match := macroDependencyPattern.FindStringSubmatch(string(source)); match != nil {
+			panic(fmt.Sprintf("%s: %s() can not be used in macros, pass the relation as an argument", macroFileName, match[1]))
+		}
+		for _, name := range names {
+			if previous, ok := declaredIn[name]; ok {
+				panic(fmt.Sprintf("%s: macro %s is already defined in %s", macroFileName, name, previous))
+			}
+			declaredIn[name] = macroFileName
+			fmt.Printf("Macro: %s (%s)\n", name, macroFileName)
+		}
+		projectProfile.Macros = append(projectProfile.Macros, &configs.MacroFile{
+			FileName: macroFileName,
+			Source:   string(source),
+			Names:    names,
+		})
+	}
+}
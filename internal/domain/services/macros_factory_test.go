@@ -0,0 +1,42 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-teal/teal/pkg/configs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMacroCallsResolveRelationsAndKeepTheCall(t *testing.T) {
+	projectPath := t.TempDir()
+	modelsDir := filepath.Join(projectPath, "assets", "models")
+	require.NoError(t, os.MkdirAll(filepath.Join(projectPath, MACROS_DIR), 0755))
+	require.NoError(t, os.MkdirAll(filepath.Join(modelsDir, "staging"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(modelsDir, "staging", "orders.sql"), []byte("select 1"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(projectPath, MACROS_DIR, "dedup.sql"), []byte(
+		"{% macro dedup(relation, key) %}select * from {{ relation }}{% endmacro %}\n"), 0644))
+
+	profile := &configs.ProjectProfile{}
+	InitMacros(&configs.Config{ProjectPath: projectPath}, profile)
+	assert.Equal(t, []string{"dedup"}, profile.MacrosMap()["dedup"].Names)
+
+	prepared, upstreams, err := prepareModelTemplate(
+		[]byte(`{{ macros.dedup(Ref("staging.orders"), "id") }} where env = '{{ ENV("ENV", "dev") }}'`),
+		"mart.orders", modelsDir, profile)
+	require.NoError(t, err)
+	sql, _ := prepared.Execute(nil)
+	assert.Equal(t, `{{ macros.dedup("staging.orders", "id") }} where env = '{{ ENV("ENV", "dev") }}'`, sql)
+	assert.Equal(t, []string{"staging.orders"}, []string(*upstreams))
+
+	assert.Panics(t, func() {
+		prepareModelTemplate([]byte(`{{ macros.missing("id") }}`), "mart.orders", modelsDir, profile)
+	})
+	require.NoError(t, os.WriteFile(filepath.Join(projectPath, MACROS_DIR, "refs.sql"), []byte(
+		"{% macro orders() %}select * from {{ Ref(\"staging.orders\") }}{% endmacro %}\n"), 0644))
+	assert.Panics(t, func() {
+		InitMacros(&configs.Config{ProjectPath: projectPath}, &configs.ProjectProfile{})
+	}, "Ref in a macro file")
+}
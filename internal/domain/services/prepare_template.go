@@ -1,6 +1,8 @@
 package services
 
 import (
+	"fmt"
+	"os"
 	"regexp"
 	"strings"
 
@@ -84,6 +86,11 @@ func extractDefineBlock(content string, blockName string) (string, string) {
 }
 
 func prepareModelTemplate(modelFileByte []byte, refName string, modelsProjetDir string, profiles *configs.ProjectProfile) (*PreparedTemplate, *utils.UpstreamDependencies, error) {
+	return prepareTemplate(modelFileByte, refName, modelsProjetDir, profiles, nil)
+}
+
+// prepareTemplate does the static pass; ephemeralChain holds the ephemeral models being inlined, to detect cycles
+func prepareTemplate(modelFileByte []byte, refName string, modelsProjetDir string, profiles *configs.ProjectProfile, ephemeralChain []string) (*PreparedTemplate, *utils.UpstreamDependencies, error) {
 	modelFileString := string(modelFileByte)
 
 	// Extract and remove profile.yaml define block before processing
@@ -115,7 +122,8 @@ func prepareModelTemplate(modelFileByte []byte, refName string, modelsProjetDir
 	// Create function context with DYNAMIC_STAB function
 	funcsContext, uniqueRefs := GetStaticFunctions(refName, modelsProjetDir, dynamicStubs, profiles)
 
-	modelFileFinalTemplate, err := pongo2.FromString(modelFileString)
+	// The stubs return template syntax for runtime, it must not be HTML escaped
+	modelFileFinalTemplate, err := pongo2.FromString("{% autoescape off %}" + modelFileString + "{% endautoescape %}")
 	if err != nil {
 		return nil, uniqueRefs, err
 	}
@@ -125,6 +133,11 @@ func prepareModelTemplate(modelFileByte []byte, refName string, modelsProjetDir
 		return nil, uniqueRefs, err
 	}
 
+	output, err = inlineEphemeralModels(output, *uniqueRefs, modelsProjetDir, profiles, append(ephemeralChain, refName))
+	if err != nil {
+		return nil, uniqueRefs, err
+	}
+
 	// Return the processed string with runtime template syntax intact
 	return &PreparedTemplate{
 		processedSQL: output,
@@ -132,3 +145,54 @@ func prepareModelTemplate(modelFileByte []byte, refName string, modelsProjetDir
 		profileYAML:  profileYAML,
 	}, uniqueRefs, nil
 }
+
+// inlineEphemeralModels compiles every referenced ephemeral model and prepends it to sqlText as a CTE
+func inlineEphemeralModels(sqlText string, refs []string, modelsProjetDir string, profiles *configs.ProjectProfile, ephemeralChain []string) (string, error) {
+	profilesMap := profiles.ToMap()
+	var ctes []string
+	for _, ref := range refs {
+		refProfile, ok := profilesMap[ref]
+		if !ok || refProfile.Materialization != configs.MAT_EPHEMERAL {
+			continue
+		}
+		for _, chained := range ephemeralChain {
+			if chained == ref {
+				return "", fmt.Errorf("ephemeral model %s references itself: %s -> %s", ref, strings.Join(ephemeralChain, " -> "), ref)
+			}
+		}
+		modelFileByte, err := os.ReadFile(modelsProjetDir + "/" + strings.Replace(ref, ".", "/", 1) + ".sql")
+		if err != nil {
+			return "", err
+		}
+		ephemeralTemplate, _, err := prepareTemplate(modelFileByte, ref, modelsProjetDir, profiles, ephemeralChain)
+		if err != nil {
+			return "", fmt.Errorf("can not inline ephemeral model %s: %w", ref, err)
+		}
+		ephemeralSQL, err := ephemeralTemplate.Execute(nil)
+		if err != nil {
+			return "", fmt.Errorf("can not inline ephemeral model %s: %w", ref, err)
+		}
+		ctes = append(ctes, refProfile.GetEphemeralName()+" as (\n"+strings.TrimSpace(ephemeralSQL)+"\n)")
+	}
+	return injectCTEs(sqlText, ctes), nil
+}
+
+// leadingWithPattern matches a leading WITH [RECURSIVE] clause, optionally preceded by line comments
+var leadingWithPattern = regexp.MustCompile(`(?is)^(\s*(?:--[^\n]*\n\s*)*)with\s+(recursive\s+)?`)
+
+// injectCTEs prepends ctes to the query, merging them into the query's own WITH clause if it has one
+func injectCTEs(sqlText string, ctes []string) string {
+	if len(ctes) == 0 {
+		return sqlText
+	}
+	cteList := strings.Join(ctes, ",\n")
+	loc := leadingWithPattern.FindStringSubmatchIndex(sqlText)
+	if loc == nil {
+		return "with " + cteList + "\n" + sqlText
+	}
+	keyword := "with "
+	if loc[4] >= 0 {
+		keyword = "with recursive "
+	}
+	return sqlText[loc[2]:loc[3]] + keyword + cteList + ",\n" + sqlText[loc[1]:]
+}
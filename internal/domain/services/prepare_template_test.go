@@ -0,0 +1,93 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-teal/teal/pkg/configs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+)
+
+func TestInjectCTEs(t *testing.T) {
+	ctes := []string{"eph_staging_a as (\nselect 1 as id\n)"}
+	cases := []struct {
+		name string
+		sql  string
+		want string
+	}{
+		{
+			name: "plain select",
+			sql:  "select id from eph_staging_a",
+			want: "with eph_staging_a as (\nselect 1 as id\n)\nselect id from eph_staging_a",
+		},
+		{
+			name: "existing with",
+			sql:  "WITH b as (select 2 as id)\nselect id from b",
+			want: "with eph_staging_a as (\nselect 1 as id\n),\nb as (select 2 as id)\nselect id from b",
+		},
+		{
+			name: "recursive with after comment",
+			sql:  "-- comment\nwith recursive b as (select 2 as id)\nselect id from b",
+			want: "-- comment\nwith recursive eph_staging_a as (\nselect 1 as id\n),\nb as (select 2 as id)\nselect id from b",
+		},
+		{
+			name: "with inside identifier",
+			sql:  "select without_tax from eph_staging_a",
+			want: "with eph_staging_a as (\nselect 1 as id\n)\nselect without_tax from eph_staging_a",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := injectCTEs(tc.sql, ctes); got != tc.want {
+				t.Errorf("injectCTEs() =\n%s\nwant\n%s", got, tc.want)
+			}
+		})
+	}
+	if got := injectCTEs("select 1", nil); got != "select 1" {
+		t.Errorf("injectCTEs() without ctes = %q", got)
+	}
+}
+
+func TestInlineNestedAndDuplicateEphemeralRefs(t *testing.T) {
+	modelsDir := t.TempDir()
+	for path, content := range map[string]string{
+		"staging/base.sql":  "select 1 as id",
+		"staging/inner.sql": `select id from {{ Ref("staging.base") }}`,
+	} {
+		require.NoError(t, os.MkdirAll(filepath.Join(modelsDir, filepath.Dir(path)), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(modelsDir, path), []byte(content), 0644))
+	}
+	profile := &configs.ProjectProfile{}
+	require.NoError(t, yaml.Unmarshal([]byte(`
+models:
+  stages:
+    - name: staging
+      models:
+        - name: base
+          materialization: ephemeral
+        - name: inner
+          materialization: ephemeral
+    - name: mart
+      models:
+        - name: orders
+`), profile))
+
+	prepared, upstreams, err := prepareModelTemplate(
+		[]byte(`select a.id from {{ Ref("staging.inner") }} a join {{ Ref("staging.inner") }} b using (id)`),
+		"mart.orders", modelsDir, profile)
+	require.NoError(t, err)
+	sql, err := prepared.Execute(nil)
+	require.NoError(t, err)
+	// The duplicate ref is inlined once, the nested ephemeral model inside the CTE of its consumer
+	assert.Equal(t, "with eph_staging_inner as (\n"+
+		"with eph_staging_base as (\nselect 1 as id\n)\nselect id from eph_staging_base\n"+
+		")\nselect a.id from eph_staging_inner a join eph_staging_inner b using (id)", sql)
+	assert.Equal(t, []string{"staging.inner"}, []string(*upstreams))
+
+	require.NoError(t, os.WriteFile(filepath.Join(modelsDir, "staging/base.sql"), []byte(`select id from {{ Ref("staging.inner") }}`), 0644))
+	_, _, err = prepareModelTemplate([]byte(`select id from {{ Ref("staging.inner") }}`), "mart.orders", modelsDir, profile)
+	assert.ErrorContains(t, err, "ephemeral model staging.inner references itself")
+}
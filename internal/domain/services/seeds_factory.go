@@ -0,0 +1,128 @@
+package services
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	internalmodels "github.com/go-teal/teal/internal/domain/internal_models"
+	"github.com/go-teal/teal/internal/domain/utils"
+	"github.com/go-teal/teal/pkg/configs"
+)
+
+const SEED_DIR = "assets/seeds"
+
+// seedFormats maps file extensions to the seed formats of pkg/drivers
+var seedFormats = map[string]string{
+	".csv":     "csv",
+	".parquet": "parquet",
+	".json":    "json",
+}
+
+type seedFile struct {
+	name     string
+	fileName string
+	format   string
+}
+
+// readSeedFiles lists the seed files of the stage, the seeds directory is optional
+func readSeedFiles(config *configs.Config, stageName string) []seedFile {
+	seedFileNames, err := os.ReadDir(config.ProjectPath + "/" + SEED_DIR + "/" + stageName)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		panic(err)
+	}
+	var seedFiles []seedFile
+	for _, seedFileNameEntry := range seedFileNames {
+		if seedFileNameEntry.IsDir() {
+			continue
+		}
+		fileName := seedFileNameEntry.Name()
+		extension := filepath.Ext(fileName)
+		format, ok := seedFormats[strings.ToLower(extension)]
+		if !ok {
+			continue
+		}
+		seedFiles = append(seedFiles, seedFile{
+			name:     strings.TrimSuffix(fileName, extension),
+			fileName: fileName,
+			format:   format,
+		})
+	}
+	return seedFiles
+}
+
+// InitSeedProfiles registers the seed files as model profiles, so that Ref("stage.seed") resolves.
+// It must be called before CombineProfiles.
+func InitSeedProfiles(config *configs.Config, projectProfile *configs.ProjectProfile) {
+	modelsProjectDir := config.ProjectPath + "/" + MODEL_DIR
+	for _, stage := range projectProfile.Models.Stages {
+		seen := make(map[string]string)
+		for _, seed := range readSeedFiles(config, stage.Name) {
+			refName := stage.Name + "." + seed.name
+			if fileName, ok := seen[seed.name]; ok {
+				panic(fmt.Sprintf("seed %s is defined by both %s and %s", refName, fileName, seed.fileName))
+			}
+			seen[seed.name] = seed.fileName
+			if isExists, _ := utils.CheckModelExists(modelsProjectDir, refName, "sql"); isExists {
+				panic(fmt.Sprintf("seed %s conflicts with the SQL model of the same name", refName))
+			}
+
+			var modelProfile *configs.ModelProfile
+			for _, m := range stage.Models {
+				if m.Name == seed.name {
+					modelProfile = m
+					break
+				}
+			}
+			if modelProfile == nil {
+				modelProfile = &configs.ModelProfile{Name: seed.name}
+				stage.Models = append(stage.Models, modelProfile)
+			}
+			if modelProfile.Materialization != "" && modelProfile.Materialization != configs.MAT_SEED {
+				panic(fmt.Sprintf("seed %s has materialization %s in profile.yaml", refName, modelProfile.Materialization))
+			}
+			modelProfile.Stage = stage.Name
+			modelProfile.Materialization = configs.MAT_SEED
+			fmt.Printf("Seed: %s (%s)\n", refName, seed.fileName)
+		}
+	}
+}
+
+func InitSeedConfigs(config *configs.Config, profiles *configs.ProjectProfile) ([]*internalmodels.ModelConfig, error) {
+	var modelsConfigs []*internalmodels.ModelConfig
+	profilesMap := profiles.ToMap()
+
+	for _, stage := range profiles.Models.Stages {
+		for _, seed := range readSeedFiles(config, stage.Name) {
+			goModelName, refName := utils.CreateModelName(stage.Name, seed.name)
+			modelProfile, ok := profilesMap[refName]
+			if !ok {
+				return nil, fmt.Errorf("profile of the seed %s is not found", refName)
+			}
+			// Parquet is read by DuckDB itself, other drivers parse the seed file in teal
+			if seed.format == "parquet" {
+				if connectionType := getConnectionType(config, modelProfile.Connection); connectionType != "duckdb" {
+					return nil, fmt.Errorf("seed %s: parquet seeds are supported by DuckDB only, connection %s is %s, use csv or json",
+						refName, modelProfile.Connection, connectionType)
+				}
+			}
+			modelsConfigs = append(modelsConfigs, &internalmodels.ModelConfig{
+				Stage:        stage.Name,
+				GoName:       goModelName,
+				ModelName:    refName,
+				ModelType:    internalmodels.SEED,
+				Config:       config,
+				Profile:      profiles,
+				ModelProfile: modelProfile,
+				SeedFilePath: SEED_DIR + "/" + stage.Name + "/" + seed.fileName,
+				SeedFormat:   seed.format,
+			})
+		}
+	}
+
+	return modelsConfigs, nil
+}
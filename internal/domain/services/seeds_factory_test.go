@@ -0,0 +1,42 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-teal/teal/pkg/configs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+)
+
+func TestParquetSeedsRequireDuckDB(t *testing.T) {
+	projectPath := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(projectPath, SEED_DIR, "staging"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(projectPath, SEED_DIR, "staging", "airports.parquet"), []byte("PAR1"), 0644))
+
+	for connectionType, wantErr := range map[string]string{
+		"duckdb":   "",
+		"postgres": "seed staging.airports: parquet seeds are supported by DuckDB only, connection default is postgres, use csv or json",
+	} {
+		t.Run(connectionType, func(t *testing.T) {
+			config := &configs.Config{
+				ProjectPath: projectPath,
+				Connections: []*configs.DBConnectionConfig{{Name: "default", Type: connectionType}},
+			}
+			profile := &configs.ProjectProfile{}
+			require.NoError(t, yaml.Unmarshal([]byte("models:\n  stages:\n    - name: staging\n"), profile))
+			InitSeedProfiles(config, profile)
+			profile.ToMap()["staging.airports"].Connection = "default"
+
+			seedConfigs, err := InitSeedConfigs(config, profile)
+			if wantErr != "" {
+				assert.EqualError(t, err, wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "parquet", seedConfigs[0].SeedFormat)
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package services
+
+import (
+	"fmt"
+
+	internalmodels "github.com/go-teal/teal/internal/domain/internal_models"
+	"github.com/go-teal/teal/internal/domain/utils"
+	"github.com/go-teal/teal/pkg/configs"
+)
+
+// InitSourceProfiles validates the sources of profile.yaml and passes the defaults of every source
+// to its tables, so that Source("source.table") resolves. It must be called before CombineProfiles.
+func InitSourceProfiles(projectProfile *configs.ProjectProfile) {
+	projectConnection := projectProfile.Connection
+	if projectConnection == "" {
+		projectConnection = "default"
+	}
+	stageNames := make(map[string]bool)
+	for _, stage := range projectProfile.Models.Stages {
+		stageNames[stage.Name] = true
+	}
+	seenSources := make(map[string]bool)
+	for _, source := range projectProfile.Sources {
+		if source.Name == "" {
+			panic("source without a name in profile.yaml")
+		}
+		if seenSources[source.Name] {
+			panic(fmt.Sprintf("source %s is declared twice", source.Name))
+		}
+		seenSources[source.Name] = true
+		if stageNames[source.Name] {
+			panic(fmt.Sprintf("source %s conflicts with the stage of the same name", source.Name))
+		}
+		seenTables := make(map[string]bool)
+		for _, table := range source.Tables {
+			refName := source.Name + "." + table.Name
+			if table.Name == "" {
+				panic(fmt.Sprintf("source %s has a table without a name", source.Name))
+			}
+			if seenTables[table.Name] {
+				panic(fmt.Sprintf("source table %s is declared twice", refName))
+			}
+			seenTables[table.Name] = true
+
+			table.Source = source.Name
+			table.Connection = source.Connection
+			if table.Connection == "" {
+				table.Connection = projectConnection
+			}
+			table.Schema = source.Schema
+			if table.Schema == "" {
+				table.Schema = source.Name
+			}
+			if table.LoadedAtField == "" {
+				table.LoadedAtField = source.LoadedAtField
+			}
+			if table.Freshness == nil {
+				table.Freshness = source.Freshness
+			}
+			if table.Freshness != nil {
+				if err := table.Freshness.Validate(); err != nil {
+					panic(fmt.Sprintf("%s: %v", refName, err))
+				}
+				if table.LoadedAtField == "" {
+					panic(fmt.Sprintf("%s: freshness requires loaded_at_field", refName))
+				}
+			}
+			fmt.Printf("Source: %s (%s)\n", refName, table.GetRelationName())
+		}
+	}
+}
+
+func InitSourceConfigs(config *configs.Config, profiles *configs.ProjectProfile) ([]*internalmodels.ModelConfig, error) {
+	var modelsConfigs []*internalmodels.ModelConfig
+
+	for _, source := range profiles.Sources {
+		for _, table := range source.Tables {
+			_, refName := utils.CreateModelName(source.Name, table.Name)
+			// the prefix keeps the Go names of sources apart from the models of similarly named stages
+			goModelName := utils.ToCamelCase("source_" + refName)
+			modelsConfigs = append(modelsConfigs, &internalmodels.ModelConfig{
+				Stage:         source.Name,
+				GoName:        goModelName,
+				ModelName:     refName,
+				ModelType:     internalmodels.EXTERNAL_SOURCE,
+				Config:        config,
+				Profile:       profiles,
+				SourceProfile: table,
+			})
+		}
+	}
+
+	return modelsConfigs, nil
+}
@@ -0,0 +1,69 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/go-teal/teal/pkg/configs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSourceResolvesToTableAndTracksDependency(t *testing.T) {
+	profile := &configs.ProjectProfile{
+		Connection: "default",
+		Sources: []*configs.SourceProfile{
+			{
+				Name:          "erp",
+				Schema:        "erp_raw",
+				LoadedAtField: "updated_at",
+				Freshness:     &configs.FreshnessProfile{WarnAfter: "12h", ErrorAfter: "24h"},
+				Tables: []*configs.SourceTableProfile{
+					{Name: "orders"},
+					{Name: "customers", LoadedAtField: "synced_at"},
+				},
+			},
+		},
+	}
+	InitSourceProfiles(profile)
+
+	orders := profile.SourcesMap()["erp.orders"]
+	assert.Equal(t, "default", orders.Connection)
+	assert.Equal(t, "erp_raw.orders", orders.GetRelationName())
+	assert.Equal(t, "updated_at", orders.LoadedAtField)
+	assert.Equal(t, "24h", orders.Freshness.ErrorAfter)
+	assert.Equal(t, "synced_at", profile.SourcesMap()["erp.customers"].LoadedAtField)
+
+	functions, upstreams := GetStaticFunctions("dds.fact_orders", t.TempDir(), map[string]string{
+		"a": `Source("erp.orders")`,
+		"b": `Source('erp.orders')`,
+	}, profile)
+	stub := functions["DYNAMIC_STAB"].(func(string) string)
+	assert.Equal(t, "erp_raw.orders", stub("a"))
+	assert.Equal(t, "erp_raw.orders", stub("b"))
+	assert.Equal(t, []string{"erp.orders"}, []string(*upstreams))
+
+	assert.Panics(t, func() {
+		functions, _ := GetStaticFunctions("dds.fact_orders", t.TempDir(), map[string]string{"c": `Source("erp.missing")`}, profile)
+		functions["DYNAMIC_STAB"].(func(string) string)("c")
+	})
+}
+
+func TestInitSourceProfilesRejectsInvalidFreshness(t *testing.T) {
+	assert.Panics(t, func() {
+		InitSourceProfiles(&configs.ProjectProfile{
+			Sources: []*configs.SourceProfile{{
+				Name:   "erp",
+				Tables: []*configs.SourceTableProfile{{Name: "orders", Freshness: &configs.FreshnessProfile{WarnAfter: "1h"}}},
+			}},
+		})
+	}, "freshness without loaded_at_field")
+	assert.Panics(t, func() {
+		InitSourceProfiles(&configs.ProjectProfile{
+			Sources: []*configs.SourceProfile{{
+				Name:          "erp",
+				LoadedAtField: "updated_at",
+				Freshness:     &configs.FreshnessProfile{WarnAfter: "24h", ErrorAfter: "1h"},
+				Tables:        []*configs.SourceTableProfile{{Name: "orders"}},
+			}},
+		})
+	}, "warn_after exceeds error_after")
+}
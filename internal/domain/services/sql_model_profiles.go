@@ -106,12 +106,35 @@ func CombineProfiles(config *configs.Config, projectProfile *configs.ProjectProf
 		stage.Models = make([]*configs.ModelProfile, 0, len(mergedProfiles))
 		for _, profile := range mergedProfiles {
 			applyDefaultsToProfile(profile, projectConnection)
-			// Set test connections and stages
+			if err := profile.ValidateRetries(); err != nil {
+				panic(fmt.Sprintf("%s.%s: %v", profile.Stage, profile.Name, err))
+			}
+			if err := configs.ValidateTags(profile.Tags); err != nil {
+				panic(fmt.Sprintf("%s.%s: %v", profile.Stage, profile.Name, err))
+			}
+			if len(profile.Grants) == 0 {
+				profile.Grants = projectProfile.Grants
+			}
+			// Set test connections and stages, name the generic tests
+			testNames := make(map[string]bool, len(profile.Tests))
 			for _, testProfile := range profile.Tests {
 				if testProfile.Connection == "" {
 					testProfile.Connection = profile.Connection
 				}
 				testProfile.Stage = profile.Stage
+				if testProfile.Type != "" {
+					if err := testProfile.ValidateGeneric(); err != nil {
+						panic(fmt.Sprintf("%s.%s: %v", profile.Stage, profile.Name, err))
+					}
+					testProfile.Name = genericTestName(profile, testProfile, testNames)
+				}
+				testNames[testProfile.Name] = true
+				if err := configs.ValidateTags(testProfile.Tags); err != nil {
+					panic(fmt.Sprintf("%s: %v", testProfile.Name, err))
+				}
+				if err := testProfile.ValidateSeverity(); err != nil {
+					panic(fmt.Sprintf("%s: %v", testProfile.Name, err))
+				}
 			}
 			stage.Models = append(stage.Models, profile)
 		}
@@ -177,9 +200,112 @@ func mergeModelProfiles(primary, secondary *configs.ModelProfile) *configs.Model
 		merged.RawUpstreams = secondary.RawUpstreams
 	}
 
+	// Merge OnViewDrift - primary has priority if not empty
+	if primary.OnViewDrift != "" {
+		merged.OnViewDrift = primary.OnViewDrift
+	} else {
+		merged.OnViewDrift = secondary.OnViewDrift
+	}
+
+	// Merge OnSchemaChange - primary has priority if not empty
+	if primary.OnSchemaChange != "" {
+		merged.OnSchemaChange = primary.OnSchemaChange
+	} else {
+		merged.OnSchemaChange = secondary.OnSchemaChange
+	}
+
+	// Merge ColumnTypes - primary has priority if not empty
+	if len(primary.ColumnTypes) > 0 {
+		merged.ColumnTypes = primary.ColumnTypes
+	} else {
+		merged.ColumnTypes = secondary.ColumnTypes
+	}
+
+	// Merge PreHooks - primary has priority if not empty
+	if len(primary.PreHooks) > 0 {
+		merged.PreHooks = primary.PreHooks
+	} else {
+		merged.PreHooks = secondary.PreHooks
+	}
+
+	// Merge PostHooks - primary has priority if not empty
+	if len(primary.PostHooks) > 0 {
+		merged.PostHooks = primary.PostHooks
+	} else {
+		merged.PostHooks = secondary.PostHooks
+	}
+
+	// Merge Grants - primary has priority if not empty
+	if len(primary.Grants) > 0 {
+		merged.Grants = primary.Grants
+	} else {
+		merged.Grants = secondary.Grants
+	}
+
+	// Merge PartitionBy - primary has priority if not empty
+	if primary.PartitionBy != nil {
+		merged.PartitionBy = primary.PartitionBy
+	} else {
+		merged.PartitionBy = secondary.PartitionBy
+	}
+
+	// Merge Export - primary has priority if not empty
+	if primary.Export != nil {
+		merged.Export = primary.Export
+	} else {
+		merged.Export = secondary.Export
+	}
+
+	// Merge Columns - primary has priority if not empty
+	if len(primary.Columns) > 0 {
+		merged.Columns = primary.Columns
+	} else {
+		merged.Columns = secondary.Columns
+	}
+
+	// Merge Tags - primary has priority if not empty
+	if len(primary.Tags) > 0 {
+		merged.Tags = primary.Tags
+	} else {
+		merged.Tags = secondary.Tags
+	}
+
+	// Merge Enabled - primary has priority if set
+	if primary.Enabled != nil {
+		merged.Enabled = primary.Enabled
+	} else {
+		merged.Enabled = secondary.Enabled
+	}
+
+	// Merge BlockDownstreamOnTestFailure - primary has priority if set
+	if primary.BlockDownstreamOnTestFailure != nil {
+		merged.BlockDownstreamOnTestFailure = primary.BlockDownstreamOnTestFailure
+	} else {
+		merged.BlockDownstreamOnTestFailure = secondary.BlockDownstreamOnTestFailure
+	}
+
+	// Merge retry policy - primary has priority if not empty
+	if primary.Retries != 0 {
+		merged.Retries = primary.Retries
+	} else {
+		merged.Retries = secondary.Retries
+	}
+	if primary.RetryDelay != "" {
+		merged.RetryDelay = primary.RetryDelay
+	} else {
+		merged.RetryDelay = secondary.RetryDelay
+	}
+	if primary.RetryBackoff != 0 {
+		merged.RetryBackoff = primary.RetryBackoff
+	} else {
+		merged.RetryBackoff = secondary.RetryBackoff
+	}
+
 	// Merge boolean fields - true takes priority
 	merged.IsDataFramed = primary.IsDataFramed || secondary.IsDataFramed
 	merged.PersistInputs = primary.PersistInputs || secondary.PersistInputs
+	merged.Atomic = primary.Atomic || secondary.Atomic
+	merged.EnforceTypes = primary.EnforceTypes || secondary.EnforceTypes
 
 	return merged
 }
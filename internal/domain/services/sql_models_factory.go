@@ -59,6 +59,71 @@ func InitSQLModelConfigs(config *configs.Config, profiles *configs.ProjectProfil
 					modelProfile.Stage = defaultModelProfile.Stage
 				}
 
+				if modelProfile.Materialization == configs.MAT_MATERIALIZED_VIEW && getConnectionType(config, modelProfile.Connection) != "postgres" {
+					fmt.Printf("Materialized views are not supported by the connection %s, %s.%s falls back to table\n", modelProfile.Connection, stageName, nameWithoutStageName)
+					modelProfile.Materialization = configs.MAT_TABLE
+				}
+
+				if modelProfile.PartitionBy != nil {
+					if err := modelProfile.PartitionBy.Validate(); err != nil {
+						panic(fmt.Sprintf("%s.%s: %v", stageName, nameWithoutStageName, err))
+					}
+					if modelProfile.Materialization != configs.MAT_TABLE && modelProfile.Materialization != configs.MAT_INCREMENTAL {
+						fmt.Printf("partition_by is supported by the table and incremental materializations only, ignored for %s.%s\n", stageName, nameWithoutStageName)
+						modelProfile.PartitionBy = nil
+					} else if getConnectionType(config, modelProfile.Connection) != "postgres" {
+						fmt.Printf("Partitioned tables are not supported by the connection %s, %s.%s is not partitioned\n", modelProfile.Connection, stageName, nameWithoutStageName)
+						modelProfile.PartitionBy = nil
+					}
+				}
+
+				if modelProfile.Materialization == configs.MAT_EXPORT {
+					if modelProfile.Export == nil {
+						panic(fmt.Sprintf("%s.%s: the export materialization requires export.path", stageName, nameWithoutStageName))
+					}
+					if modelProfile.Export.Format == "" {
+						modelProfile.Export.Format = configs.EXPORT_CSV
+					}
+					if err := modelProfile.Export.Validate(); err != nil {
+						panic(fmt.Sprintf("%s.%s: %v", stageName, nameWithoutStageName, err))
+					}
+					if modelProfile.Export.Format == configs.EXPORT_PARQUET && getConnectionType(config, modelProfile.Connection) != "duckdb" {
+						panic(fmt.Sprintf("%s.%s: parquet export is supported by DuckDB only", stageName, nameWithoutStageName))
+					}
+					if modelProfile.IsDataFramed {
+						// The output of an export is the list of the written files
+						panic(fmt.Sprintf("%s.%s: export can not be combined with is_data_framed", stageName, nameWithoutStageName))
+					}
+				} else if modelProfile.Export != nil {
+					fmt.Printf("export is supported by the export materialization only, ignored for %s.%s\n", stageName, nameWithoutStageName)
+					modelProfile.Export = nil
+				}
+
+				if err := modelProfile.ValidateColumns(); err != nil {
+					panic(fmt.Sprintf("%s.%s: %v", stageName, nameWithoutStageName, err))
+				}
+				if len(modelProfile.Columns) > 0 {
+					switch modelProfile.Materialization {
+					case configs.MAT_TABLE, configs.MAT_INCREMENTAL, configs.MAT_VIEW, configs.MAT_MATERIALIZED_VIEW:
+					default:
+						panic(fmt.Sprintf("%s.%s: columns can not be declared for the %s materialization, contracts are checked for tables, incremental models and views only", stageName, nameWithoutStageName, modelProfile.Materialization))
+					}
+				}
+
+				if modelProfile.Atomic {
+					if getConnectionType(config, modelProfile.Connection) != "postgres" {
+						fmt.Printf("Atomic execution is not supported by the connection %s, ignored for %s.%s\n", modelProfile.Connection, stageName, nameWithoutStageName)
+						modelProfile.Atomic = false
+					} else if modelProfile.IsDataFramed && modelProfile.PersistInputs {
+						// The DataFrame is read outside of the transaction and can not see the persisted inputs
+						panic(fmt.Sprintf("%s.%s: atomic can not be combined with is_data_framed and persist_inputs", stageName, nameWithoutStageName))
+					}
+				}
+				if modelProfile.Retries > 0 && !modelProfile.Atomic && modelProfile.Materialization != configs.MAT_EPHEMERAL && !modelProfile.IsRepeatableMaterialization() {
+					// A partial execution can not be repeated without applying its changes twice
+					panic(fmt.Sprintf("%s.%s: retries require atomic execution or a table, incremental, view or materialized_view materialization without pre_hooks", stageName, nameWithoutStageName))
+				}
+
 				modelFileByte, err := os.ReadFile(modelsProjectDir + "/" + stageName + "/" + originalName)
 				if err != nil {
 					panic(err)
@@ -73,6 +138,11 @@ func InitSQLModelConfigs(config *configs.Config, profiles *configs.ProjectProfil
 				if err != nil {
 					return nil, err
 				}
+				var contractSQL string
+				if modelProfile.EnforceTypes {
+					contractSQL = sqlString
+					sqlString = castToContract(sqlString, modelProfile.Columns)
+				}
 				sqlByteBuffer := *bytes.NewBufferString(sqlString)
 
 				data := &internalmodels.ModelConfig{
@@ -81,6 +151,7 @@ func InitSQLModelConfigs(config *configs.Config, profiles *configs.ProjectProfil
 					Stage:         stage.Name,
 					NameUpperCase: fmt.Sprintf("%s_%s", strings.ToUpper(stageName), strings.ToUpper(strings.ReplaceAll(originalName, ".sql", ""))),
 					SqlByteBuffer: sqlByteBuffer,
+					ContractSQL:   contractSQL,
 					Config:        config,
 					Profile:       profiles,
 					Upstreams:     *uniqueRefs,
@@ -108,3 +179,22 @@ func InitSQLModelConfigs(config *configs.Config, profiles *configs.ProjectProfil
 
 	return modelsConfigs, nil
 }
+
+// castToContract selects the columns of the contract from the model query, cast to their types
+func castToContract(sqlString string, columns []*configs.ColumnProfile) string {
+	casts := make([]string, len(columns))
+	for i, column := range columns {
+		casts[i] = fmt.Sprintf("cast(\"%s\" as %s) as \"%s\"", column.Name, column.Type, column.Name)
+	}
+	// The query becomes a subquery, its terminating semicolon is removed
+	return fmt.Sprintf("select %s\nfrom (\n%s\n) as teal_contract", strings.Join(casts, ", "), strings.TrimSuffix(strings.TrimSpace(sqlString), ";"))
+}
+
+func getConnectionType(config *configs.Config, connectionName string) string {
+	for _, connection := range config.Connections {
+		if connection.Name == connectionName {
+			return connection.Type
+		}
+	}
+	return ""
+}
@@ -0,0 +1,20 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/go-teal/teal/pkg/configs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCastToContractQuotesColumnsAndStripsSemicolon(t *testing.T) {
+	columns := []*configs.ColumnProfile{
+		{Name: "OrderId", Type: "bigint"},
+		{Name: "order", Type: "varchar"},
+	}
+
+	assert.Equal(t,
+		"select cast(\"OrderId\" as bigint) as \"OrderId\", cast(\"order\" as varchar) as \"order\"\n"+
+			"from (\nselect id as \"OrderId\", name as \"order\" from staging.orders\n) as teal_contract",
+		castToContract("select id as \"OrderId\", name as \"order\" from staging.orders;\n", columns))
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"os"
+	"regexp"
 	"strings"
 
 	internalmodels "github.com/go-teal/teal/internal/domain/internal_models"
@@ -53,9 +54,191 @@ func InitSQLTestsConfigs(config *configs.Config, projectProfile *configs.Project
 		}
 	}
 
+	declared := make(map[string]bool, len(testConfigs))
+	for _, testConfig := range testConfigs {
+		declared[testConfig.TestName] = true
+	}
+	for _, testConfig := range initGenericTestConfigs(projectProfile, modelsProjectDir) {
+		if declared[testConfig.TestName] {
+			panic(fmt.Sprintf("test %s is declared twice: by a file of %s and by a generic test", testConfig.TestName, TESTS_DIR))
+		}
+		declared[testConfig.TestName] = true
+		testConfigs = append(testConfigs, testConfig)
+	}
+
 	return testConfigs, nil
 }
 
+// initGenericTestConfigs expands the generic tests of the model profiles into SQL tests
+func initGenericTestConfigs(projectProfile *configs.ProjectProfile, modelsProjectDir string) []*internalmodels.TestConfig {
+	var testConfigs []*internalmodels.TestConfig
+	for _, stage := range projectProfile.Models.Stages {
+		for _, modelProfile := range stage.Models {
+			for _, testProfile := range modelProfile.Tests {
+				if testProfile.Type == "" {
+					continue
+				}
+				if testProfile.Description == "" {
+					testProfile.Description = genericTestDescription(testProfile)
+				}
+				stageName, fileName, _ := strings.Cut(testProfile.Name, ".")
+				sqlTemplate := genericTestSQL(stage.Name+"."+modelProfile.Name, testProfile)
+				testConfig, err := initGenericTestConfig(stageName, fileName, sqlTemplate, testProfile, projectProfile, modelsProjectDir)
+				if err != nil {
+					fmt.Printf("can not expand generic test %s\n", testProfile.Name)
+					panic(err)
+				}
+				testConfigs = append(testConfigs, testConfig)
+			}
+		}
+	}
+	return testConfigs
+}
+
+// initGenericTestConfig renders the SQL of a generic test like the SQL of a test file
+func initGenericTestConfig(
+	stage string,
+	name string,
+	sqlTemplate string,
+	testProfile *configs.TestProfile,
+	projectProfile *configs.ProjectProfile,
+	modelsProjectDir string,
+) (*internalmodels.TestConfig, error) {
+	goFuncName, refName := utils.CreateModelName(stage, name)
+	testTemplate, _, err := prepareModelTemplate([]byte(sqlTemplate), refName, modelsProjectDir, projectProfile)
+	if err != nil {
+		return nil, err
+	}
+	sqlString, err := testTemplate.Execute(nil)
+	if err != nil {
+		return nil, err
+	}
+	return &internalmodels.TestConfig{
+		TestName:      refName,
+		GoName:        goFuncName,
+		NameUpperCase: strings.ToUpper(fmt.Sprintf("%s_%s", stage, name)),
+		SqlByteBuffer: *bytes.NewBufferString(sqlString),
+		TestProfile:   testProfile,
+	}, nil
+}
+
+// genericTestName returns the name of a generic test: the declared name with the stage of the model,
+// or <stage>.<type>_<model>_<column> with a numeric suffix for repeated tests
+func genericTestName(modelProfile *configs.ModelProfile, testProfile *configs.TestProfile, used map[string]bool) string {
+	if testProfile.Name != "" {
+		if !testNamePattern.MatchString(testProfile.Name) {
+			panic(fmt.Sprintf("%s.%s: invalid test name %q, expected name or stage.name of letters, digits and underscores", modelProfile.Stage, modelProfile.Name, testProfile.Name))
+		}
+		if strings.Contains(testProfile.Name, ".") {
+			return testProfile.Name
+		}
+		return modelProfile.Stage + "." + testProfile.Name
+	}
+	parts := []string{string(testProfile.Type), modelProfile.Name}
+	switch testProfile.Type {
+	case configs.GENERIC_TEST_UNIQUE, configs.GENERIC_TEST_NOT_NULL, configs.GENERIC_TEST_ACCEPTED_VALUES, configs.GENERIC_TEST_RELATIONSHIPS:
+		for _, column := range splitColumns(testProfile.Column) {
+			parts = append(parts, column)
+		}
+	}
+	baseName := modelProfile.Stage + "." + strings.ToLower(nonIdentifierChars.ReplaceAllString(strings.Join(parts, "_"), "_"))
+	name := baseName
+	for i := 2; used[name]; i++ {
+		name = fmt.Sprintf("%s_%d", baseName, i)
+	}
+	return name
+}
+
+var (
+	nonIdentifierChars = regexp.MustCompile(`[^A-Za-z0-9_]+`)
+	testNamePattern    = regexp.MustCompile(`^([A-Za-z0-9_]+\.)?[A-Za-z0-9_]+$`)
+)
+
+func splitColumns(columns string) []string {
+	var result []string
+	for _, column := range strings.Split(columns, ",") {
+		if column = strings.TrimSpace(column); column != "" {
+			result = append(result, column)
+		}
+	}
+	return result
+}
+
+// genericTestSQL returns the template of a generic test, it selects the rows violating the test
+func genericTestSQL(modelName string, test *configs.TestProfile) string {
+	relation := fmt.Sprintf(`{{ Ref("%s") }}`, modelName)
+	if test.Where != "" {
+		relation = fmt.Sprintf("(select * from %s where %s)", relation, test.Where)
+	}
+	switch test.Type {
+	case configs.GENERIC_TEST_UNIQUE:
+		columns := strings.Join(splitColumns(test.Column), ", ")
+		return fmt.Sprintf("select %s, count(*) as duplicate_count\nfrom %s as teal_model\ngroup by %s\nhaving count(*) > 1", columns, relation, columns)
+	case configs.GENERIC_TEST_NOT_NULL:
+		return fmt.Sprintf("select *\nfrom %s as teal_model\nwhere %s is null", relation, test.Column)
+	case configs.GENERIC_TEST_ACCEPTED_VALUES:
+		values := make([]string, len(test.Values))
+		for i, value := range test.Values {
+			values[i] = "'" + strings.ReplaceAll(value, "'", "''") + "'"
+		}
+		return fmt.Sprintf("select %s, count(*) as value_count\nfrom %s as teal_model\nwhere %s not in (%s)\ngroup by %s",
+			test.Column, relation, test.Column, strings.Join(values, ", "), test.Column)
+	case configs.GENERIC_TEST_RELATIONSHIPS:
+		field := test.Field
+		if field == "" {
+			field = test.Column
+		}
+		return fmt.Sprintf("select teal_model.%s\nfrom %s as teal_model\nleft join {{ Ref(\"%s\") }} as teal_parent on teal_model.%s = teal_parent.%s\nwhere teal_model.%s is not null and teal_parent.%s is null",
+			test.Column, relation, test.To, test.Column, field, test.Column, field)
+	case configs.GENERIC_TEST_EXPRESSION_IS_TRUE:
+		return fmt.Sprintf("select *\nfrom %s as teal_model\nwhere not (%s)", relation, test.Expression)
+	case configs.GENERIC_TEST_ROW_COUNT_BETWEEN:
+		var conditions []string
+		if test.Min != nil {
+			conditions = append(conditions, fmt.Sprintf("count(*) < %d", *test.Min))
+		}
+		if test.Max != nil {
+			conditions = append(conditions, fmt.Sprintf("count(*) > %d", *test.Max))
+		}
+		return fmt.Sprintf("select count(*) as row_count\nfrom %s as teal_model\nhaving %s", relation, strings.Join(conditions, " or "))
+	}
+	panic(fmt.Sprintf("unknown generic test type %q", test.Type))
+}
+
+// genericTestDescription describes a generic test without a description for the UI and the documentation
+func genericTestDescription(test *configs.TestProfile) string {
+	var description string
+	switch test.Type {
+	case configs.GENERIC_TEST_UNIQUE:
+		description = fmt.Sprintf("%s is unique", test.Column)
+	case configs.GENERIC_TEST_NOT_NULL:
+		description = fmt.Sprintf("%s is not null", test.Column)
+	case configs.GENERIC_TEST_ACCEPTED_VALUES:
+		description = fmt.Sprintf("%s is one of %s", test.Column, strings.Join(test.Values, ", "))
+	case configs.GENERIC_TEST_RELATIONSHIPS:
+		field := test.Field
+		if field == "" {
+			field = test.Column
+		}
+		description = fmt.Sprintf("%s references %s.%s", test.Column, test.To, field)
+	case configs.GENERIC_TEST_EXPRESSION_IS_TRUE:
+		description = fmt.Sprintf("%s is true for every row", test.Expression)
+	case configs.GENERIC_TEST_ROW_COUNT_BETWEEN:
+		switch {
+		case test.Min == nil:
+			description = fmt.Sprintf("at most %d rows", *test.Max)
+		case test.Max == nil:
+			description = fmt.Sprintf("at least %d rows", *test.Min)
+		default:
+			description = fmt.Sprintf("%d to %d rows", *test.Min, *test.Max)
+		}
+	}
+	if test.Where != "" {
+		description += fmt.Sprintf(" (where %s)", test.Where)
+	}
+	return fmt.Sprintf("Generic test %s: %s", test.Type, description)
+}
+
 func initTestConfig(
 	stage string,
 	fullPath string,
@@ -89,6 +272,27 @@ func initTestConfig(
 		if newTestProfile.Description != "" {
 			globalTestProfile.Description = newTestProfile.Description
 		}
+		if len(newTestProfile.Tags) > 0 {
+			if err = configs.ValidateTags(newTestProfile.Tags); err != nil {
+				panic(fmt.Sprintf("%s: %v", refName, err))
+			}
+			globalTestProfile.Tags = newTestProfile.Tags
+		}
+		if newTestProfile.Enabled != nil {
+			globalTestProfile.Enabled = newTestProfile.Enabled
+		}
+		if newTestProfile.Severity != "" {
+			globalTestProfile.Severity = newTestProfile.Severity
+		}
+		if newTestProfile.ErrorIf != "" {
+			globalTestProfile.ErrorIf = newTestProfile.ErrorIf
+		}
+		if newTestProfile.WarnIf != "" {
+			globalTestProfile.WarnIf = newTestProfile.WarnIf
+		}
+	}
+	if err = globalTestProfile.ValidateSeverity(); err != nil {
+		panic(fmt.Sprintf("%s: %v", refName, err))
 	}
 
 	sqlString, err := testFileFinalTemplate.Execute(nil)
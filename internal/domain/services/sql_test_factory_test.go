@@ -0,0 +1,78 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/go-teal/teal/pkg/configs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+)
+
+func TestGenericTestsExpansion(t *testing.T) {
+	modelProfile := &configs.ModelProfile{Name: "fact_orders", Stage: "dds"}
+	require.NoError(t, yaml.Unmarshal([]byte(`
+tests:
+  - name: dds.test_orders_custom
+  - type: unique
+    column: order_id, line_id
+  - type: accepted_values
+    column: status
+    values: [new, "customer's"]
+    where: created_at >= '{{ IntervalStart() }}'
+  - type: relationships
+    column: customer_id
+    to: dds.dim_customers
+  - type: expression_is_true
+    expression: amount >= 0
+  - type: expression_is_true
+    expression: quantity > 0
+  - type: row_count_between
+    min: 1
+    max: 1000
+  - name: orders_pk
+    type: not_null
+    column: order_id
+`), modelProfile))
+
+	used := map[string]bool{"dds.test_orders_custom": true}
+	var names []string
+	for _, test := range modelProfile.Tests[1:] {
+		require.NoError(t, test.ValidateGeneric())
+		name := genericTestName(modelProfile, test, used)
+		used[name] = true
+		names = append(names, name)
+	}
+	assert.Equal(t, []string{
+		"dds.unique_fact_orders_order_id_line_id",
+		"dds.accepted_values_fact_orders_status",
+		"dds.relationships_fact_orders_customer_id",
+		"dds.expression_is_true_fact_orders",
+		"dds.expression_is_true_fact_orders_2",
+		"dds.row_count_between_fact_orders",
+		"dds.orders_pk",
+	}, names)
+
+	assert.Equal(t, `select order_id, line_id, count(*) as duplicate_count
+from {{ Ref("dds.fact_orders") }} as teal_model
+group by order_id, line_id
+having count(*) > 1`, genericTestSQL("dds.fact_orders", modelProfile.Tests[1]))
+	assert.Equal(t, `select status, count(*) as value_count
+from (select * from {{ Ref("dds.fact_orders") }} where created_at >= '{{ IntervalStart() }}') as teal_model
+where status not in ('new', 'customer''s')
+group by status`, genericTestSQL("dds.fact_orders", modelProfile.Tests[2]))
+	assert.Equal(t, `select teal_model.customer_id
+from {{ Ref("dds.fact_orders") }} as teal_model
+left join {{ Ref("dds.dim_customers") }} as teal_parent on teal_model.customer_id = teal_parent.customer_id
+where teal_model.customer_id is not null and teal_parent.customer_id is null`, genericTestSQL("dds.fact_orders", modelProfile.Tests[3]))
+	assert.Equal(t, `select count(*) as row_count
+from {{ Ref("dds.fact_orders") }} as teal_model
+having count(*) < 1 or count(*) > 1000`, genericTestSQL("dds.fact_orders", modelProfile.Tests[6]))
+	assert.Equal(t, "Generic test row_count_between: 1 to 1000 rows", genericTestDescription(modelProfile.Tests[6]))
+
+	assert.EqualError(t, (&configs.TestProfile{Type: "unique"}).ValidateGeneric(), "unique requires column")
+	assert.EqualError(t, (&configs.TestProfile{Type: "relationships", Column: "customer_id", To: "dim_customers"}).ValidateGeneric(),
+		`relationships.to must be a model name stage.model, got "dim_customers"`)
+	assert.EqualError(t, (&configs.TestProfile{Type: "between"}).ValidateGeneric(), `unknown generic test type "between"`)
+	assert.Panics(t, func() { genericTestName(modelProfile, &configs.TestProfile{Name: "orders pk"}, used) })
+}
@@ -10,6 +10,13 @@ import (
 	"github.com/go-teal/teal/pkg/configs"
 )
 
+var (
+	sourcePattern    = regexp.MustCompile(`Source\s*\(\s*["']([^"']+)["']\s*\)`)
+	refPattern       = regexp.MustCompile(`Ref\s*\(\s*["']([^"']+)["']\s*\)`)
+	macroCallPattern = regexp.MustCompile(`\bmacros\.([A-Za-z_][A-Za-z0-9_]*)\s*\(`)
+	varPattern       = regexp.MustCompile(`\bVar\s*\(\s*["']([^"']+)["']\s*([,)])`)
+)
+
 func GetStaticFunctions(
 	refName string,
 	modelsProjetDir string,
@@ -18,51 +25,109 @@ func GetStaticFunctions(
 ) (pongo2.Context, *utils.UpstreamDependencies) {
 	var uniqueRefs *utils.UpstreamDependencies = &utils.UpstreamDependencies{}
 	profilesMap := profiles.ToMap()
+	sourcesMap := profiles.SourcesMap()
+	macrosMap := profiles.MacrosMap()
+
+	trackDependency := func(ref string) {
+		for _, r := range *uniqueRefs {
+			if r == ref {
+				return
+			}
+		}
+		*uniqueRefs = append(*uniqueRefs, ref)
+	}
+
+	// resolveSource returns the table of a profile.yaml source and tracks the dependency
+	resolveSource := func(ref string) string {
+		sourceTable, ok := sourcesMap[ref]
+		if !ok {
+			panic(fmt.Sprintf("Source %s not found", ref))
+		}
+		trackDependency(ref)
+		return sourceTable.GetRelationName()
+	}
+
+	// resolveRef returns the relation a model refers to and tracks the dependency
+	resolveRef := func(ref string) string {
+		// Validate model exists
+		isExists, err := utils.CheckModelExists(modelsProjetDir, ref, "sql")
+		_, isRawProfileExist := profilesMap[ref]
+		isExists = isExists || isRawProfileExist
+		if !isExists {
+			fmt.Println(err)
+			panic(fmt.Sprintf("Model %s not found", ref))
+		}
+
+		// Track dependency (avoid duplicates)
+		trackDependency(ref)
+
+		// Ephemeral models are inlined as CTEs, refer to the CTE alias
+		currentProfile, okCurrentProfile := profilesMap[refName]
+		refProfile, okRefProfile := profilesMap[ref]
+		if okRefProfile && refProfile.Materialization == configs.MAT_EXPORT {
+			panic(fmt.Sprintf("Model %s exports files and has no relation to refer to", ref))
+		}
+		if okRefProfile && refProfile.Materialization == configs.MAT_EPHEMERAL {
+			return refProfile.GetEphemeralName()
+		}
+
+		// Tests of data framed raw assets read the DataFrame persisted by RawModelAsset.RunTests
+		if !okCurrentProfile && okRefProfile && refProfile.Materialization == configs.MAT_RAW && refProfile.IsDataFramed {
+			return refProfile.GetTestTempName()
+		}
+
+		// Check if we need temp name for dataframed models
+		if okCurrentProfile && okRefProfile {
+			if currentProfile.PersistInputs && refProfile.IsDataFramed {
+				return profilesMap[ref].GetTempName()
+			}
+		}
+
+		// Return the model name for SQL
+		return ref
+	}
 
 	// DYNAMIC_STAB handles all pongo2 template syntax
 	dynamicStabFunc := func(stubHash string) string {
 		originalContent := dynamicStubs[stubHash]
 
+		// Var("name") without a default must be declared in the vars of profile.yaml, Var itself is evaluated at runtime
+		for _, varCall := range varPattern.FindAllStringSubmatch(originalContent, -1) {
+			if _, ok := profiles.Vars[varCall[1]]; !ok && varCall[2] == ")" {
+				panic(fmt.Sprintf("Var %s is not declared in the vars of profile.yaml and has no default", varCall[1]))
+			}
+		}
+
+		// Check if it calls macros - the macros must exist, the relations passed to them are resolved
+		// to string literals and the call itself is rendered at runtime
+		if macroCalls := macroCallPattern.FindAllStringSubmatch(originalContent, -1); len(macroCalls) > 0 {
+			for _, macroCall := range macroCalls {
+				if _, ok := macrosMap[macroCall[1]]; !ok {
+					panic(fmt.Sprintf("Macro %s not found in %s", macroCall[1], MACROS_DIR))
+				}
+			}
+			content := sourcePattern.ReplaceAllStringFunc(originalContent, func(match string) string {
+				return `"` + resolveSource(sourcePattern.FindStringSubmatch(match)[1]) + `"`
+			})
+			return refPattern.ReplaceAllStringFunc(content, func(match string) string {
+				return `"` + resolveRef(refPattern.FindStringSubmatch(match)[1]) + `"`
+			})
+		}
+
+		// Check if it contains Source(...) - resolve the table of profile.yaml sources and track dependency
+		if strings.Contains(originalContent, "Source(") {
+			matches := sourcePattern.FindStringSubmatch(originalContent)
+			if len(matches) > 1 {
+				return resolveSource(matches[1]) // e.g., "erp.orders"
+			}
+		}
+
 		// Check if it contains Ref(...) - extract model name and track dependency
 		if strings.Contains(originalContent, "Ref(") {
 			// Extract the argument: Ref("stage.model") or Ref('stage.model')
-			refPattern := regexp.MustCompile(`Ref\s*\(\s*["']([^"']+)["']\s*\)`)
 			matches := refPattern.FindStringSubmatch(originalContent)
 			if len(matches) > 1 {
-				ref := matches[1] // e.g., "staging.model_name"
-
-				// Validate model exists
-				isExists, err := utils.CheckModelExists(modelsProjetDir, ref, "sql")
-				_, isRawProfileExist := profilesMap[ref]
-				isExists = isExists || isRawProfileExist
-				if !isExists {
-					fmt.Println(err)
-					panic(fmt.Sprintf("Model %s not found", ref))
-				}
-
-				// Track dependency (avoid duplicates)
-				alreadyTracked := false
-				for _, r := range *uniqueRefs {
-					if r == ref {
-						alreadyTracked = true
-						break
-					}
-				}
-				if !alreadyTracked {
-					*uniqueRefs = append(*uniqueRefs, ref)
-				}
-
-				// Check if we need temp name for dataframed models
-				currentProfile, okCurrentProfile := profilesMap[refName]
-				refProfile, okRefProfile := profilesMap[ref]
-				if okCurrentProfile && okRefProfile {
-					if currentProfile.PersistInputs && refProfile.IsDataFramed {
-						return profilesMap[ref].GetTempName()
-					}
-				}
-
-				// Return the model name for SQL
-				return ref
+				return resolveRef(matches[1]) // e.g., "staging.model_name"
 			}
 		}
 
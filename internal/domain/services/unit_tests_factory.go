@@ -0,0 +1,266 @@
+package services
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	internalmodels "github.com/go-teal/teal/internal/domain/internal_models"
+	"github.com/go-teal/teal/internal/domain/utils"
+	"github.com/go-teal/teal/pkg/configs"
+	"github.com/go-teal/teal/pkg/models"
+	"github.com/go-teal/teal/pkg/processing"
+	"gopkg.in/yaml.v2"
+)
+
+const UNIT_TESTS_DIR = "assets/unit_tests"
+
+// InitUnitTestConfigs reads the unit tests of assets/unit_tests, the directory is optional.
+// The model configs must have the execution upstreams resolved by the dependency graph.
+func InitUnitTestConfigs(
+	config *configs.Config,
+	projectProfile *configs.ProjectProfile,
+	modelConfigs []*internalmodels.ModelConfig,
+) ([]*internalmodels.UnitTestConfig, error) {
+	unitTestsDir := config.ProjectPath + "/" + UNIT_TESTS_DIR
+	if _, err := os.Stat(unitTestsDir); os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	type unitTestFile struct {
+		stage string
+		path  string
+	}
+	var files []unitTestFile
+	dirs := []unitTestFile{{stage: "root", path: unitTestsDir}}
+	for _, stage := range projectProfile.Models.Stages {
+		dirs = append(dirs, unitTestFile{stage: stage.Name, path: unitTestsDir + "/" + stage.Name})
+	}
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir.path)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			extension := filepath.Ext(entry.Name())
+			if !entry.IsDir() && (extension == ".yaml" || extension == ".yml") {
+				files = append(files, unitTestFile{stage: dir.stage, path: dir.path + "/" + entry.Name()})
+			}
+		}
+	}
+
+	modelConfigsMap := make(map[string]*internalmodels.ModelConfig, len(modelConfigs))
+	for _, modelConfig := range modelConfigs {
+		modelConfigsMap[modelConfig.ModelName] = modelConfig
+	}
+
+	var unitTestConfigs []*internalmodels.UnitTestConfig
+	for _, file := range files {
+		fileName := filepath.Base(file.path)
+		goName, testName := utils.CreateModelName(file.stage, strings.TrimSuffix(fileName, filepath.Ext(fileName)))
+		unitTestFile, err := os.ReadFile(file.path)
+		if err != nil {
+			return nil, err
+		}
+		var profile configs.UnitTestProfile
+		if err = yaml.Unmarshal(unitTestFile, &profile); err != nil {
+			return nil, fmt.Errorf("%s: %w", testName, err)
+		}
+		unitTestConfig, err := initUnitTestConfig(testName, &profile, projectProfile, modelConfigsMap)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", testName, err)
+		}
+		unitTestConfig.GoName = goName
+		unitTestConfigs = append(unitTestConfigs, unitTestConfig)
+	}
+	return unitTestConfigs, nil
+}
+
+// initUnitTestConfig validates the unit test against the model and renders the SQL of its fixtures and expected rows
+func initUnitTestConfig(
+	testName string,
+	profile *configs.UnitTestProfile,
+	projectProfile *configs.ProjectProfile,
+	modelConfigs map[string]*internalmodels.ModelConfig,
+) (*internalmodels.UnitTestConfig, error) {
+	if err := profile.Validate(); err != nil {
+		return nil, err
+	}
+	modelConfig, ok := modelConfigs[profile.Model]
+	if !ok || modelConfig.ModelType != internalmodels.DATABASE {
+		return nil, fmt.Errorf("model %s is not a SQL model", profile.Model)
+	}
+	if modelConfig.ModelProfile.Materialization == configs.MAT_EPHEMERAL {
+		return nil, fmt.Errorf("model %s is ephemeral, test the models using it", profile.Model)
+	}
+	for name := range profile.Vars {
+		if _, ok := projectProfile.Vars[name]; !ok {
+			return nil, fmt.Errorf("var %s is not declared in the vars of profile.yaml", name)
+		}
+	}
+	if _, err := processing.ParseRunDate(profile.RunDate); err != nil {
+		return nil, fmt.Errorf("run_date: %w", err)
+	}
+	varsJSON, err := profile.VarsJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	sourcesMap := projectProfile.SourcesMap()
+	given := make(map[string]bool, len(profile.Given))
+	fixtures := make([]*models.UnitTestFixture, 0, len(profile.Given))
+	for _, fixtureProfile := range profile.Given {
+		name := fixtureProfile.GetName()
+		if given[name] {
+			return nil, fmt.Errorf("%s is given twice", name)
+		}
+		given[name] = true
+		if !slices.Contains(modelConfig.Upstreams, name) {
+			return nil, fmt.Errorf("%s is not an upstream of %s, the upstreams are %s",
+				name, profile.Model, strings.Join(modelConfig.Upstreams, ", "))
+		}
+		_, isSource := sourcesMap[name]
+		if fixtureProfile.Source != "" && !isSource {
+			return nil, fmt.Errorf("%s is not a table of profile.yaml sources", name)
+		}
+		if fixtureProfile.Ref != "" && isSource {
+			return nil, fmt.Errorf("%s is a table of profile.yaml sources, give it as source", name)
+		}
+		fixtureSQL, _, err := unitTestRowsSQL(&fixtureProfile.UnitTestRowsProfile)
+		if err != nil {
+			return nil, fmt.Errorf("given %s: %w", name, err)
+		}
+		fixtures = append(fixtures, &models.UnitTestFixture{
+			Name:     name,
+			Relation: unitTestFixtureRelation(name, modelConfig, projectProfile, modelConfigs),
+			SQL:      fixtureSQL,
+		})
+	}
+	var missing []string
+	for _, upstream := range modelConfig.Upstreams {
+		if !given[upstream] {
+			missing = append(missing, upstream)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("upstreams %s of %s are not given", strings.Join(missing, ", "), profile.Model)
+	}
+
+	expectedSQL, expectedColumns, err := unitTestRowsSQL(profile.Expect)
+	if err != nil {
+		return nil, fmt.Errorf("expect: %w", err)
+	}
+
+	return &internalmodels.UnitTestConfig{
+		TestName:        testName,
+		ModelName:       profile.Model,
+		Profile:         profile,
+		Fixtures:        fixtures,
+		ExpectedSQL:     expectedSQL,
+		ExpectedColumns: expectedColumns,
+		VarsJSON:        varsJSON,
+	}, nil
+}
+
+// unitTestFixtureRelation returns the relation the rendered SQL of the model refers to for the upstream, see GetStaticFunctions
+func unitTestFixtureRelation(
+	name string,
+	modelConfig *internalmodels.ModelConfig,
+	projectProfile *configs.ProjectProfile,
+	modelConfigs map[string]*internalmodels.ModelConfig,
+) string {
+	if sourceTable, ok := projectProfile.SourcesMap()[name]; ok {
+		return sourceTable.GetRelationName()
+	}
+	if upstream, ok := modelConfigs[name]; ok && upstream.ModelProfile != nil {
+		if modelConfig.ModelProfile.PersistInputs && upstream.ModelProfile.IsDataFramed {
+			return upstream.ModelProfile.GetTempName()
+		}
+	}
+	return name
+}
+
+// unitTestRowsSQL renders the rows as a union of selects and returns their columns: the declared columns,
+// then the columns of the rows in the order of appearance. The values of the declared columns are cast to their types.
+func unitTestRowsSQL(rows *configs.UnitTestRowsProfile) (string, []string, error) {
+	var columns []string
+	types := make(map[string]string)
+	for _, column := range rows.Columns {
+		name := fmt.Sprint(column.Key)
+		columns = append(columns, name)
+		types[name] = fmt.Sprint(column.Value)
+	}
+	values := make([]map[string]string, len(rows.Rows))
+	for i, row := range rows.Rows {
+		values[i] = make(map[string]string, len(row))
+		for _, item := range row {
+			name := fmt.Sprint(item.Key)
+			literal, err := unitTestLiteral(item.Value)
+			if err != nil {
+				return "", nil, fmt.Errorf("rows[%d].%s: %w", i, name, err)
+			}
+			if !slices.Contains(columns, name) {
+				columns = append(columns, name)
+			}
+			values[i][name] = literal
+		}
+	}
+
+	selectRow := func(row map[string]string) string {
+		fields := make([]string, len(columns))
+		for i, column := range columns {
+			value, ok := row[column]
+			if !ok {
+				value = "null"
+			}
+			if columnType, ok := types[column]; ok {
+				value = fmt.Sprintf("cast(%s as %s)", value, columnType)
+			}
+			fields[i] = fmt.Sprintf("%s as %s", value, quoteIdentifier(column))
+		}
+		return "select " + strings.Join(fields, ", ")
+	}
+
+	if len(values) == 0 {
+		return selectRow(nil) + " where false", columns, nil
+	}
+	selects := make([]string, len(values))
+	for i, row := range values {
+		selects[i] = selectRow(row)
+	}
+	return strings.Join(selects, "\nunion all\n"), columns, nil
+}
+
+// unitTestLiteral renders a YAML value as a SQL literal
+func unitTestLiteral(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "null", nil
+	case string:
+		return "'" + strings.ReplaceAll(v, "'", "''") + "'", nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case int:
+		return strconv.Itoa(v), nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case uint64:
+		return strconv.FormatUint(v, 10), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case time.Time:
+		if v.Equal(v.Truncate(24 * time.Hour)) {
+			return "'" + v.Format(processing.RUN_DATE_LAYOUT) + "'", nil
+		}
+		return "'" + v.Format(processing.INTERVAL_LAYOUT) + "'", nil
+	}
+	return "", fmt.Errorf("unsupported value %v, expected a string, a number, a boolean or null", value)
+}
+
+func quoteIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
@@ -0,0 +1,125 @@
+package services
+
+import (
+	"testing"
+
+	internalmodels "github.com/go-teal/teal/internal/domain/internal_models"
+	"github.com/go-teal/teal/pkg/configs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+)
+
+func TestUnitTestRowsSQL(t *testing.T) {
+	var rows configs.UnitTestRowsProfile
+	require.NoError(t, yaml.Unmarshal([]byte(`
+columns:
+  created_at: timestamp
+rows:
+  - {order_id: 1, status: "customer's", created_at: '2024-01-01 10:00:00'}
+  - {order_id: 2.5, paid: true, status: null}
+`), &rows))
+
+	sql, columns, err := unitTestRowsSQL(&rows)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"created_at", "order_id", "status", "paid"}, columns)
+	assert.Equal(t,
+		`select cast('2024-01-01 10:00:00' as timestamp) as "created_at", 1 as "order_id", 'customer''s' as "status", null as "paid"`+"\n"+
+			"union all\n"+
+			`select cast(null as timestamp) as "created_at", 2.5 as "order_id", null as "status", true as "paid"`,
+		sql)
+
+	empty := configs.UnitTestRowsProfile{Columns: yaml.MapSlice{{Key: "order_id", Value: "integer"}}}
+	sql, columns, err = unitTestRowsSQL(&empty)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"order_id"}, columns)
+	assert.Equal(t, `select cast(null as integer) as "order_id" where false`, sql)
+
+	nested := configs.UnitTestRowsProfile{Rows: []yaml.MapSlice{{{Key: "items", Value: []interface{}{1, 2}}}}}
+	_, _, err = unitTestRowsSQL(&nested)
+	assert.ErrorContains(t, err, "rows[0].items")
+}
+
+func TestInitUnitTestConfig(t *testing.T) {
+	projectProfile := &configs.ProjectProfile{
+		Vars: map[string]interface{}{"min_amount": 0},
+		Sources: []*configs.SourceProfile{
+			{Name: "erp", Tables: []*configs.SourceTableProfile{{Name: "orders", Schema: "raw_erp"}}},
+		},
+	}
+	modelConfigs := map[string]*internalmodels.ModelConfig{
+		"dds.fact_orders": {
+			ModelName:    "dds.fact_orders",
+			ModelType:    internalmodels.DATABASE,
+			Upstreams:    []string{"erp.orders", "staging.customers"},
+			ModelProfile: &configs.ModelProfile{Materialization: configs.MAT_TABLE, PersistInputs: true},
+		},
+		"staging.customers": {
+			ModelName:    "staging.customers",
+			ModelType:    internalmodels.DATABASE,
+			ModelProfile: &configs.ModelProfile{Name: "customers", Stage: "staging", IsDataFramed: true},
+		},
+	}
+	parse := func(source string) *configs.UnitTestProfile {
+		var profile configs.UnitTestProfile
+		require.NoError(t, yaml.Unmarshal([]byte(source), &profile))
+		return &profile
+	}
+
+	unitTestConfig, err := initUnitTestConfig("dds.fact_orders_totals", parse(`
+model: dds.fact_orders
+vars: {min_amount: 10}
+run_date: '2024-01-01'
+given:
+  - source: erp.orders
+    rows: [{order_id: 1, customer_id: 1}]
+  - ref: staging.customers
+    columns: {customer_id: integer}
+expect:
+  rows: [{order_id: 1}]
+`), projectProfile, modelConfigs)
+	require.NoError(t, err)
+	require.Len(t, unitTestConfig.Fixtures, 2)
+	assert.Equal(t, "raw_erp.orders", unitTestConfig.Fixtures[0].Relation)
+	assert.Equal(t, "tmp_staging_customers", unitTestConfig.Fixtures[1].Relation)
+	assert.Equal(t, []string{"order_id"}, unitTestConfig.ExpectedColumns)
+	assert.Equal(t, `{"min_amount":10}`, unitTestConfig.VarsJSON)
+
+	for name, testCase := range map[string]struct {
+		source string
+		err    string
+	}{
+		"unknown model": {`
+model: dds.unknown
+expect: {rows: [{a: 1}]}`, "model dds.unknown is not a SQL model"},
+		"not an upstream": {`
+model: dds.fact_orders
+given:
+  - ref: staging.orders
+    rows: [{a: 1}]
+expect: {rows: [{a: 1}]}`, "staging.orders is not an upstream of dds.fact_orders"},
+		"missing upstream": {`
+model: dds.fact_orders
+given:
+  - source: erp.orders
+    rows: [{a: 1}]
+expect: {rows: [{a: 1}]}`, "upstreams staging.customers of dds.fact_orders are not given"},
+		"source given as ref": {`
+model: dds.fact_orders
+given:
+  - ref: erp.orders
+    rows: [{a: 1}]
+expect: {rows: [{a: 1}]}`, "give it as source"},
+		"undeclared var": {`
+model: dds.fact_orders
+vars: {max_amount: 10}
+expect: {rows: [{a: 1}]}`, "var max_amount is not declared"},
+		"without expectation": {`
+model: dds.fact_orders`, "expect requires rows or columns"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := initUnitTestConfig("dds.fact_orders_totals", parse(testCase.source), projectProfile, modelConfigs)
+			assert.ErrorContains(t, err, testCase.err)
+		})
+	}
+}
@@ -1,5 +1,13 @@
 package configs
 
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
 type MatType string
 
 const (
@@ -8,18 +16,99 @@ const (
 	MAT_INCREMENTAL MatType = "incremental"
 	MAT_CUSTOM      MatType = "custom"
 	MAT_RAW         MatType = "raw"
+	MAT_EPHEMERAL   MatType = "ephemeral"
+	// MAT_MATERIALIZED_VIEW is supported by PostgreSQL only, other databases fall back to MAT_TABLE
+	MAT_MATERIALIZED_VIEW MatType = "materialized_view"
+	// MAT_SEED is set for files of assets/seeds, it can not be used for SQL models
+	MAT_SEED MatType = "seed"
+	// MAT_EXPORT writes the result of the query to files instead of a relation, see ExportProfile
+	MAT_EXPORT MatType = "export"
+)
+
+// Severities of tests
+const (
+	TEST_SEVERITY_ERROR = "error"
+	TEST_SEVERITY_WARN  = "warn"
+)
+
+// GenericTestType is a built-in test declared in the tests of a model profile
+type GenericTestType string
+
+const (
+	GENERIC_TEST_UNIQUE             GenericTestType = "unique"
+	GENERIC_TEST_NOT_NULL           GenericTestType = "not_null"
+	GENERIC_TEST_ACCEPTED_VALUES    GenericTestType = "accepted_values"
+	GENERIC_TEST_RELATIONSHIPS      GenericTestType = "relationships"
+	GENERIC_TEST_EXPRESSION_IS_TRUE GenericTestType = "expression_is_true"
+	GENERIC_TEST_ROW_COUNT_BETWEEN  GenericTestType = "row_count_between"
+)
+
+type ViewDriftPolicy string
+
+const (
+	VIEW_DRIFT_REPLACE ViewDriftPolicy = "replace"
+	VIEW_DRIFT_FAIL    ViewDriftPolicy = "fail"
+)
+
+type OnSchemaChange string
+
+const (
+	ON_SCHEMA_CHANGE_IGNORE             OnSchemaChange = "ignore"
+	ON_SCHEMA_CHANGE_FAIL               OnSchemaChange = "fail"
+	ON_SCHEMA_CHANGE_APPEND_NEW_COLUMNS OnSchemaChange = "append_new_columns"
+	ON_SCHEMA_CHANGE_SYNC_ALL_COLUMNS   OnSchemaChange = "sync_all_columns"
+)
+
+type PartitionType string
+
+const (
+	PARTITION_RANGE PartitionType = "range"
+	PARTITION_LIST  PartitionType = "list"
+)
+
+// Formats of export files
+const (
+	EXPORT_CSV     = "csv"
+	EXPORT_PARQUET = "parquet"
+	EXPORT_JSON    = "json"
+)
+
+// Granularities of range partitions
+const (
+	PARTITION_DAY   = "day"
+	PARTITION_MONTH = "month"
+	PARTITION_YEAR  = "year"
 )
 
 type ProjectProfile struct {
 	Version    string `yaml:"version"`
 	Name       string `yaml:"name"`
 	Connection string `yaml:"connection"`
+	// OnRunStart and OnRunEnd are SQL statements executed on the project connection
+	// before the first and after the last asset of every task
+	OnRunStart []string `yaml:"on_run_start"`
+	OnRunEnd   []string `yaml:"on_run_end"`
 	Models     struct {
 		Stages []*struct {
 			Name   string          `yaml:"name"`
 			Models []*ModelProfile `yaml:"models"`
 		} `yaml:"stages"`
 	} `yaml:"models"`
+	// Grants are the default grants of the models without their own grants
+	Grants map[string][]string `yaml:"grants"`
+	// Sources declare the external tables the models read with Source("source.table")
+	Sources []*SourceProfile `yaml:"sources"`
+	// Vars are the defaults of the Var("name") template function, overridable with --vars
+	Vars map[string]interface{} `yaml:"vars"`
+	// Macros are the files of assets/macros, loaded by teal gen
+	Macros []*MacroFile `yaml:"-"`
+}
+
+// MacroFile is a file of assets/macros with the macros it declares
+type MacroFile struct {
+	FileName string
+	Source   string
+	Names    []string
 }
 
 type ModelProfile struct {
@@ -34,6 +123,307 @@ type ModelProfile struct {
 	Stage            string         `yaml:"-"`
 	Tests            []*TestProfile `yaml:"tests"`
 	RawUpstreams     []string       `yaml:"raw_upstreams"`
+	// OnViewDrift defines what to do when the SQL of an existing view differs from the model
+	OnViewDrift ViewDriftPolicy `yaml:"on_view_drift"`
+	// OnSchemaChange defines how an incremental model reacts to columns added to or removed from its query
+	OnSchemaChange OnSchemaChange `yaml:"on_schema_change"`
+	// ColumnTypes overrides the inferred types of seed columns
+	ColumnTypes map[string]string `yaml:"column_types"`
+	// PreHooks and PostHooks are SQL statements executed before and after the materialization
+	PreHooks  []string `yaml:"pre_hooks"`
+	PostHooks []string `yaml:"post_hooks"`
+	// Grants maps privileges to roles, applied after every create or replace (PostgreSQL only)
+	Grants map[string][]string `yaml:"grants"`
+	// PartitionBy declares a partitioned table (PostgreSQL only, table and incremental materializations)
+	PartitionBy *PartitionProfile `yaml:"partition_by"`
+	// Atomic executes the whole materialization in one transaction (PostgreSQL only)
+	Atomic bool `yaml:"atomic"`
+	// Retries is the number of re-executions after retryable errors
+	Retries int `yaml:"retries"`
+	// RetryDelay is the pause before the first retry, e.g. 500ms or 5s
+	RetryDelay string `yaml:"retry_delay"`
+	// RetryBackoff multiplies the pause after every retry, 1 keeps it constant
+	RetryBackoff float64 `yaml:"retry_backoff"`
+	// Export defines the files of the export materialization
+	Export *ExportProfile `yaml:"export"`
+	// Columns is the contract of the relation, checked at runtime
+	Columns []*ColumnProfile `yaml:"columns"`
+	// EnforceTypes casts the columns of the query to the types of the contract
+	EnforceTypes bool `yaml:"enforce_types"`
+	// Tags group models for --select and --exclude, e.g. tag:daily
+	Tags []string `yaml:"tags"`
+	// Enabled is true when not set, disabled models are generated but never executed
+	Enabled *bool `yaml:"enabled"`
+	// BlockDownstreamOnTestFailure is true when not set, the downstreams of a model
+	// with failed error severity tests are not executed
+	BlockDownstreamOnTestFailure *bool `yaml:"block_downstream_on_test_failure"`
+}
+
+// IsEnabled reports whether the model is executed by the DAG
+func (p *ModelProfile) IsEnabled() bool {
+	return p.Enabled == nil || *p.Enabled
+}
+
+// BlocksDownstreamOnTestFailure reports whether failed tests of the model stop its downstreams
+func (p *ModelProfile) BlocksDownstreamOnTestFailure() bool {
+	return p.BlockDownstreamOnTestFailure == nil || *p.BlockDownstreamOnTestFailure
+}
+
+// ValidateRetries checks the retry policy
+func (p *ModelProfile) ValidateRetries() error {
+	if p.Retries < 0 {
+		return fmt.Errorf("retries must not be negative, got %d", p.Retries)
+	}
+	if p.RetryDelay != "" {
+		delay, err := time.ParseDuration(p.RetryDelay)
+		if err != nil {
+			return fmt.Errorf("retry_delay: %w", err)
+		}
+		if delay < 0 {
+			return fmt.Errorf("retry_delay must not be negative, got %s", p.RetryDelay)
+		}
+	}
+	if p.RetryBackoff != 0 && p.RetryBackoff < 1 {
+		return fmt.Errorf("retry_backoff must be at least 1, got %v", p.RetryBackoff)
+	}
+	return nil
+}
+
+// IsRepeatableMaterialization reports whether a non-atomic SQL model can be executed again after its materialization failed.
+// Tables and views are rebuilt and incremental inserts are rolled back entirely; pre-hooks are committed before
+// the materialization and would run twice, custom queries and exports may leave partial changes behind.
+func (p *ModelProfile) IsRepeatableMaterialization() bool {
+	if len(p.PreHooks) > 0 {
+		return false
+	}
+	switch p.Materialization {
+	case MAT_TABLE, MAT_INCREMENTAL, MAT_VIEW, MAT_MATERIALIZED_VIEW:
+		return true
+	}
+	return false
+}
+
+// ValidateTags checks that the tags can be used in selection expressions
+func ValidateTags(tags []string) error {
+	for _, tag := range tags {
+		if tag == "" || strings.ContainsAny(tag, " \t\n,:+\"'`") {
+			return fmt.Errorf("invalid tag %q, tags must not be empty or contain spaces, quotes or any of ,:+", tag)
+		}
+	}
+	return nil
+}
+
+type PartitionProfile struct {
+	Type   PartitionType `yaml:"type"`
+	Column string        `yaml:"column"`
+	// Granularity is required by range partitions: day, month or year
+	Granularity string `yaml:"granularity"`
+	// ReplacePartitions makes incremental runs replace the partitions of the inserted rows instead of appending to them
+	ReplacePartitions bool `yaml:"replace_partitions"`
+}
+
+// Validate checks the partitioning declaration
+func (p *PartitionProfile) Validate() error {
+	if p.Column == "" {
+		return fmt.Errorf("partition_by.column is required")
+	}
+	switch p.Type {
+	case PARTITION_RANGE:
+		switch p.Granularity {
+		case PARTITION_DAY, PARTITION_MONTH, PARTITION_YEAR:
+		default:
+			return fmt.Errorf("partition_by.granularity must be day, month or year, got %q", p.Granularity)
+		}
+	case PARTITION_LIST:
+	default:
+		return fmt.Errorf("partition_by.type must be range or list, got %q", p.Type)
+	}
+	return nil
+}
+
+// SourceProfile declares a group of external tables, which are loaded outside of the project.
+// Connection, schema, loaded_at_field and freshness are the defaults of its tables.
+type SourceProfile struct {
+	Name          string                `yaml:"name"`
+	Description   string                `yaml:"description"`
+	Connection    string                `yaml:"connection"`
+	Schema        string                `yaml:"schema"`
+	LoadedAtField string                `yaml:"loaded_at_field"`
+	Freshness     *FreshnessProfile     `yaml:"freshness"`
+	Tables        []*SourceTableProfile `yaml:"tables"`
+}
+
+type SourceTableProfile struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description"`
+	// LoadedAtField is the timestamp column the freshness is measured by,
+	// without it only the existence of the table is checked
+	LoadedAtField string            `yaml:"loaded_at_field"`
+	Freshness     *FreshnessProfile `yaml:"freshness"`
+	// Source, Connection and Schema are inherited from the source
+	Source     string `yaml:"-"`
+	Connection string `yaml:"-"`
+	Schema     string `yaml:"-"`
+}
+
+// FreshnessProfile defines how old the newest row of a source table may be, e.g. 12h or 90m.
+// Exceeding WarnAfter logs a warning, exceeding ErrorAfter fails the source and blocks its downstreams.
+type FreshnessProfile struct {
+	WarnAfter  string `yaml:"warn_after"`
+	ErrorAfter string `yaml:"error_after"`
+}
+
+// Validate checks the thresholds
+func (f *FreshnessProfile) Validate() error {
+	var warnAfter, errorAfter time.Duration
+	var err error
+	if f.WarnAfter != "" {
+		if warnAfter, err = time.ParseDuration(f.WarnAfter); err != nil {
+			return fmt.Errorf("freshness.warn_after: %w", err)
+		}
+	}
+	if f.ErrorAfter != "" {
+		if errorAfter, err = time.ParseDuration(f.ErrorAfter); err != nil {
+			return fmt.Errorf("freshness.error_after: %w", err)
+		}
+	}
+	if warnAfter < 0 || errorAfter < 0 {
+		return fmt.Errorf("freshness thresholds must not be negative")
+	}
+	if warnAfter > 0 && errorAfter > 0 && warnAfter > errorAfter {
+		return fmt.Errorf("freshness.warn_after %s exceeds freshness.error_after %s", f.WarnAfter, f.ErrorAfter)
+	}
+	return nil
+}
+
+// GetRelationName returns the qualified name of the table in the database
+func (t *SourceTableProfile) GetRelationName() string {
+	return t.Schema + "." + t.Name
+}
+
+// SourcesMap maps "source.table" to the source tables
+func (p ProjectProfile) SourcesMap() map[string]*SourceTableProfile {
+	sourcesMap := make(map[string]*SourceTableProfile)
+	for _, source := range p.Sources {
+		for _, table := range source.Tables {
+			sourcesMap[source.Name+"."+table.Name] = table
+		}
+	}
+	return sourcesMap
+}
+
+// VarsJSON encodes the vars as a JSON object
+func (p ProjectProfile) VarsJSON() (string, error) {
+	encoded, err := json.Marshal(stringKeys(p.Vars))
+	if err != nil {
+		return "", fmt.Errorf("vars: %w", err)
+	}
+	return string(encoded), nil
+}
+
+// stringKeys converts the maps decoded from YAML to maps with string keys, which JSON can encode
+func stringKeys(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		converted := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			converted[fmt.Sprint(key)] = stringKeys(item)
+		}
+		return converted
+	case map[string]interface{}:
+		converted := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			converted[key] = stringKeys(item)
+		}
+		return converted
+	case []interface{}:
+		converted := make([]interface{}, len(v))
+		for i, item := range v {
+			converted[i] = stringKeys(item)
+		}
+		return converted
+	}
+	return value
+}
+
+// MacrosMap maps the macro names to the files declaring them
+func (p ProjectProfile) MacrosMap() map[string]*MacroFile {
+	macrosMap := make(map[string]*MacroFile)
+	for _, file := range p.Macros {
+		for _, name := range file.Names {
+			macrosMap[name] = file
+		}
+	}
+	return macrosMap
+}
+
+type ExportProfile struct {
+	// Format is csv (default), parquet (DuckDB only) or json (newline delimited)
+	Format string `yaml:"format"`
+	// Path is a template rendered at runtime, e.g. exports/orders_{{ TaskID }}.csv.
+	// With PartitionBy it is the directory of <column>=<value> subdirectories.
+	Path string `yaml:"path"`
+	// PartitionBy splits the files by the values of the columns, which are not written to the files
+	PartitionBy []string `yaml:"partition_by"`
+}
+
+// Validate checks the export declaration
+func (e *ExportProfile) Validate() error {
+	if e.Path == "" {
+		return fmt.Errorf("export.path is required")
+	}
+	// The path is generated into a Go raw string literal
+	if strings.Contains(e.Path, "`") {
+		return fmt.Errorf("export.path must not contain backticks, got %q", e.Path)
+	}
+	switch e.Format {
+	case EXPORT_CSV, EXPORT_PARQUET, EXPORT_JSON:
+	default:
+		return fmt.Errorf("export.format must be csv, parquet or json, got %q", e.Format)
+	}
+	return nil
+}
+
+// ColumnProfile is a column of the contract of a model
+type ColumnProfile struct {
+	Name string `yaml:"name"`
+	// Type is the database type of the column, an empty type is not checked
+	Type string `yaml:"type"`
+	// Nullable is true by default, not nullable columns are checked for nulls after the materialization
+	Nullable    bool   `yaml:"nullable"`
+	Description string `yaml:"description"`
+}
+
+// UnmarshalYAML makes columns nullable unless declared otherwise
+func (c *ColumnProfile) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type plain ColumnProfile
+	column := plain{Nullable: true}
+	if err := unmarshal(&column); err != nil {
+		return err
+	}
+	*c = ColumnProfile(column)
+	return nil
+}
+
+// ValidateColumns checks the contract of the model
+func (p *ModelProfile) ValidateColumns() error {
+	if p.EnforceTypes && len(p.Columns) == 0 {
+		return fmt.Errorf("enforce_types requires columns")
+	}
+	declared := make(map[string]bool, len(p.Columns))
+	for i, column := range p.Columns {
+		if column.Name == "" {
+			return fmt.Errorf("columns[%d].name is required", i)
+		}
+		if declared[strings.ToLower(column.Name)] {
+			return fmt.Errorf("column %s is declared twice", column.Name)
+		}
+		declared[strings.ToLower(column.Name)] = true
+		if p.EnforceTypes && column.Type == "" {
+			return fmt.Errorf("column %s has no type, enforce_types requires the types of all columns", column.Name)
+		}
+	}
+	return nil
 }
 
 type DBIndex struct {
@@ -47,12 +437,133 @@ type TestProfile struct {
 	Description string `yaml:"description"`
 	Connection  string `yaml:"connection"`
 	Stage       string `yaml:"-"`
+	// Tags allow to exclude tests with --exclude, e.g. tag:slow
+	Tags []string `yaml:"tags"`
+	// Enabled is true when not set, disabled tests are skipped
+	Enabled *bool `yaml:"enabled"`
+	// Type declares a generic test of the model, expanded by teal gen into a SQL test, see GenericTestType
+	Type GenericTestType `yaml:"type"`
+	// Column is the tested column, unique also takes a comma separated list of columns
+	Column string `yaml:"column"`
+	// Values are the accepted values of accepted_values
+	Values []string `yaml:"values"`
+	// To and Field are the referenced model and column of relationships, Field defaults to Column
+	To    string `yaml:"to"`
+	Field string `yaml:"field"`
+	// Expression is the SQL condition of expression_is_true, every row must satisfy it
+	Expression string `yaml:"expression"`
+	// Min and Max bound the number of rows of row_count_between, inclusive
+	Min *int64 `yaml:"min"`
+	Max *int64 `yaml:"max"`
+	// Where filters the rows of the model checked by a generic test
+	Where string `yaml:"where"`
+	// Severity is error (default) or warn, failures of a warn test are reported as warnings
+	Severity string `yaml:"severity"`
+	// ErrorIf and WarnIf are conditions on the number of failing rows, e.g. "> 10", both are "!= 0" by default
+	ErrorIf string `yaml:"error_if"`
+	WarnIf  string `yaml:"warn_if"`
+}
+
+// IsEnabled reports whether the test is executed
+func (p *TestProfile) IsEnabled() bool {
+	return p.Enabled == nil || *p.Enabled
+}
+
+// ValidateSeverity checks the severity and the conditions of the test
+func (p *TestProfile) ValidateSeverity() error {
+	switch p.Severity {
+	case "", TEST_SEVERITY_ERROR, TEST_SEVERITY_WARN:
+	default:
+		return fmt.Errorf("severity must be error or warn, got %q", p.Severity)
+	}
+	if _, err := EvaluateTestCondition(p.ErrorIf, 0); err != nil {
+		return fmt.Errorf("error_if: %w", err)
+	}
+	if _, err := EvaluateTestCondition(p.WarnIf, 0); err != nil {
+		return fmt.Errorf("warn_if: %w", err)
+	}
+	return nil
+}
+
+// EvaluateTestCondition compares the number of failing rows with a condition: an operator
+// (>, >=, <, <=, =, ==, !=, <>) and a number, an empty condition is "!= 0"
+func EvaluateTestCondition(condition string, failures int64) (bool, error) {
+	condition = strings.TrimSpace(condition)
+	if condition == "" {
+		return failures != 0, nil
+	}
+	operator := strings.TrimRight(condition, " 0123456789-")
+	threshold, err := strconv.ParseInt(strings.TrimSpace(condition[len(operator):]), 10, 64)
+	if err != nil {
+		return false, fmt.Errorf("invalid condition %q, expected an operator and a number, e.g. > 10", condition)
+	}
+	switch strings.TrimSpace(operator) {
+	case ">":
+		return failures > threshold, nil
+	case ">=":
+		return failures >= threshold, nil
+	case "<":
+		return failures < threshold, nil
+	case "<=":
+		return failures <= threshold, nil
+	case "=", "==":
+		return failures == threshold, nil
+	case "!=", "<>":
+		return failures != threshold, nil
+	}
+	return false, fmt.Errorf("invalid condition %q, the operator must be one of >, >=, <, <=, =, ==, !=, <>", condition)
+}
+
+// ValidateGeneric checks the parameters of a generic test
+func (p *TestProfile) ValidateGeneric() error {
+	switch p.Type {
+	case GENERIC_TEST_UNIQUE, GENERIC_TEST_NOT_NULL:
+		if p.Column == "" {
+			return fmt.Errorf("%s requires column", p.Type)
+		}
+	case GENERIC_TEST_ACCEPTED_VALUES:
+		if p.Column == "" || len(p.Values) == 0 {
+			return fmt.Errorf("accepted_values requires column and values")
+		}
+	case GENERIC_TEST_RELATIONSHIPS:
+		if p.Column == "" || p.To == "" {
+			return fmt.Errorf("relationships requires column and to")
+		}
+		if !strings.Contains(p.To, ".") {
+			return fmt.Errorf("relationships.to must be a model name stage.model, got %q", p.To)
+		}
+	case GENERIC_TEST_EXPRESSION_IS_TRUE:
+		if p.Expression == "" {
+			return fmt.Errorf("expression_is_true requires expression")
+		}
+	case GENERIC_TEST_ROW_COUNT_BETWEEN:
+		if p.Min == nil && p.Max == nil {
+			return fmt.Errorf("row_count_between requires min or max")
+		}
+		if p.Min != nil && p.Max != nil && *p.Min > *p.Max {
+			return fmt.Errorf("row_count_between.min %d exceeds max %d", *p.Min, *p.Max)
+		}
+	default:
+		return fmt.Errorf("unknown generic test type %q", p.Type)
+	}
+	return nil
 }
 
 func (mp *ModelProfile) GetTempName() string {
 	return "tmp_" + mp.Stage + "_" + mp.Name
 }
 
+// GetTestTempName returns the relation the DataFrame of a data framed raw asset is written to while its tests run.
+// It differs from GetTempName, which persist_inputs of the downstream models writes to.
+func (mp *ModelProfile) GetTestTempName() string {
+	return mp.GetTempName() + "__test"
+}
+
+// GetEphemeralName returns the CTE alias used when an ephemeral model is inlined into its consumers.
+func (mp *ModelProfile) GetEphemeralName() string {
+	return "eph_" + mp.Stage + "_" + mp.Name
+}
+
 func (p ProjectProfile) ToMap() map[string]*ModelProfile {
 	profilesMap := make(map[string]*ModelProfile)
 	for _, s := range p.Models.Stages {
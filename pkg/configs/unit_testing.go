@@ -0,0 +1,67 @@
+package configs
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v2"
+)
+
+// UnitTestProfile is a file of assets/unit_tests: fixture rows for the upstreams of a model and the rows it must return
+type UnitTestProfile struct {
+	Model       string `yaml:"model"`
+	Description string `yaml:"description"`
+	// Vars override the vars of profile.yaml, RunDate is the logical date of the test (today by default)
+	Vars    map[string]interface{}    `yaml:"vars"`
+	RunDate string                    `yaml:"run_date"`
+	Given   []*UnitTestFixtureProfile `yaml:"given"`
+	Expect  *UnitTestRowsProfile      `yaml:"expect"`
+}
+
+// UnitTestFixtureProfile replaces an upstream model (ref) or a table of profile.yaml sources (source)
+type UnitTestFixtureProfile struct {
+	Ref                 string `yaml:"ref"`
+	Source              string `yaml:"source"`
+	UnitTestRowsProfile `yaml:",inline"`
+}
+
+// UnitTestRowsProfile are rows of a fixture or of the expectation
+type UnitTestRowsProfile struct {
+	// Columns cast the values to the SQL types, e.g. created_at: timestamp, and declare the columns of empty rows
+	Columns yaml.MapSlice   `yaml:"columns"`
+	Rows    []yaml.MapSlice `yaml:"rows"`
+}
+
+// GetName returns the upstream replaced by the fixture
+func (f *UnitTestFixtureProfile) GetName() string {
+	if f.Source != "" {
+		return f.Source
+	}
+	return f.Ref
+}
+
+// Validate checks the model, the fixtures and the expectation of the unit test
+func (p *UnitTestProfile) Validate() error {
+	if p.Model == "" {
+		return fmt.Errorf("model is required")
+	}
+	for i, fixture := range p.Given {
+		if (fixture.Ref == "") == (fixture.Source == "") {
+			return fmt.Errorf("given[%d] requires either ref or source", i)
+		}
+		if len(fixture.Rows) == 0 && len(fixture.Columns) == 0 {
+			return fmt.Errorf("given %s requires rows or columns", fixture.GetName())
+		}
+	}
+	if p.Expect == nil || (len(p.Expect.Rows) == 0 && len(p.Expect.Columns) == 0) {
+		return fmt.Errorf("expect requires rows or columns")
+	}
+	return nil
+}
+
+// VarsJSON encodes the vars of the unit test as a JSON object, empty without vars
+func (p *UnitTestProfile) VarsJSON() (string, error) {
+	if len(p.Vars) == 0 {
+		return "", nil
+	}
+	return ProjectProfile{Vars: p.Vars}.VarsJSON()
+}
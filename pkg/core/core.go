@@ -74,6 +74,11 @@ func (c *Core) GetDBConnection(connection string) drivers.DBDriver {
 	return dbConnection
 }
 
+// SetDBConnection registers an established connection under the name, replacing the connection of config.yaml
+func (c *Core) SetDBConnection(connection string, dbConnection drivers.DBDriver) {
+	c.dbConnections[connection] = dbConnection
+}
+
 // getAvailableConnectionNames returns list of configured connection names for error messages
 func (c *Core) getAvailableConnectionNames() []string {
 	names := make([]string, 0, len(c.dbConnections))
@@ -0,0 +1,109 @@
+package dags
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-teal/teal/pkg/processing"
+	"github.com/rs/zerolog/log"
+)
+
+// BackfillResult is the result of the task of one interval of a backfill
+type BackfillResult struct {
+	TaskID        string
+	IntervalStart time.Time
+	IntervalEnd   time.Time
+	Result        map[string]interface{}
+}
+
+// BackfillIntervals splits [start, end) into intervals of step, the last interval ends at end
+func BackfillIntervals(start time.Time, end time.Time, step time.Duration) ([][2]time.Time, error) {
+	if start.IsZero() || end.IsZero() {
+		return nil, fmt.Errorf("a backfill needs the start and the end of its range")
+	}
+	if !end.After(start) {
+		return nil, fmt.Errorf("backfill end %s must be after backfill start %s", end.Format(time.RFC3339), start.Format(time.RFC3339))
+	}
+	if step <= 0 {
+		return nil, fmt.Errorf("backfill step must be positive")
+	}
+	var intervals [][2]time.Time
+	for intervalStart := start; intervalStart.Before(end); intervalStart = intervalStart.Add(step) {
+		intervalEnd := intervalStart.Add(step)
+		if intervalEnd.After(end) {
+			intervalEnd = end
+		}
+		intervals = append(intervals, [2]time.Time{intervalStart, intervalEnd})
+	}
+	return intervals, nil
+}
+
+// backfillTaskID names the task of an interval after its start, daily intervals by the date only
+func backfillTaskID(taskId string, intervalStart time.Time, step time.Duration) string {
+	if step%(24*time.Hour) == 0 && intervalStart.Equal(intervalStart.Truncate(24*time.Hour)) {
+		return taskId + "_" + intervalStart.Format("20060102")
+	}
+	return taskId + "_" + intervalStart.Format("20060102T150405")
+}
+
+// Backfill pushes one task per interval of [options.IntervalStart, options.IntervalEnd), the run date of a task
+// is the start of its interval. Tasks are pushed in the order of the intervals, at most concurrency of them
+// run at once. The results are in the order of the intervals. A full refresh applies to the first interval only.
+func Backfill(dag DAG, taskId string, data interface{}, options *processing.RunOptions, step time.Duration, concurrency int) ([]BackfillResult, error) {
+	if options == nil {
+		return nil, fmt.Errorf("a backfill needs the start and the end of its range")
+	}
+	intervals, err := BackfillIntervals(options.IntervalStart, options.IntervalEnd, step)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := processing.ResolveVars(options.Vars); err != nil {
+		return nil, err
+	}
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	log.Info().
+		Str("taskId", taskId).
+		Time("start", options.IntervalStart).
+		Time("end", options.IntervalEnd).
+		Int("intervals", len(intervals)).
+		Int("concurrency", concurrency).
+		Msg("Starting backfill")
+
+	results := make([]BackfillResult, len(intervals))
+	slots := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	// Tasks are pushed from this goroutine only, so that every asset receives them in the same order
+	for i, interval := range intervals {
+		slots <- struct{}{}
+		taskOptions := *options
+		taskOptions.RunDate = interval[0]
+		taskOptions.IntervalStart = interval[0]
+		taskOptions.IntervalEnd = interval[1]
+		// The tables are rebuilt by the first task, the following tasks append to them
+		taskOptions.FullRefresh = options.FullRefresh && i == 0
+		results[i] = BackfillResult{
+			TaskID:        backfillTaskID(taskId, interval[0], step),
+			IntervalStart: interval[0],
+			IntervalEnd:   interval[1],
+		}
+		resultChan := dag.PushWithOptions(results[i].TaskID, data, &taskOptions, make(chan map[string]interface{}, 1))
+
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i].Result = <-resultChan
+			<-slots
+			log.Info().
+				Str("taskId", results[i].TaskID).
+				Time("intervalStart", results[i].IntervalStart).
+				Time("intervalEnd", results[i].IntervalEnd).
+				Msg("Backfill task complete")
+		}(i)
+	}
+	wg.Wait()
+	return results, nil
+}
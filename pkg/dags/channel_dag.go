@@ -35,6 +35,7 @@ type ChannelDag struct {
 	config               *configs.Config
 	completeTasksResults map[string]*taskResult
 	numberOfFinalTasks   int
+	runHooks             *processing.RunHooks
 	// mu guards completeTasksResults and each taskResult.results map.
 	// Leaf-node goroutines write concurrently when a task completes.
 	mu sync.RWMutex
@@ -112,6 +113,8 @@ type TransitionTask struct {
 	Data         interface{}
 	StopSignal   bool
 	IngoreSignal bool
+	// Options are the resolved options of the task
+	Options *processing.RunOptions
 }
 
 func initDagRoutine(dag *ChannelDag,
@@ -149,6 +152,11 @@ func initDagRoutine(dag *ChannelDag,
 }
 
 func (dag *ChannelDag) Push(taskId string, data interface{}, resultChan chan map[string]interface{}) chan map[string]interface{} {
+	return dag.PushWithOptions(taskId, data, nil, resultChan)
+}
+
+// PushWithOptions implements DAG.
+func (dag *ChannelDag) PushWithOptions(taskId string, data interface{}, options *processing.RunOptions, resultChan chan map[string]interface{}) chan map[string]interface{} {
 	taskUUID := uuid.New().String()
 
 	if resultChan != nil {
@@ -164,19 +172,63 @@ func (dag *ChannelDag) Push(taskId string, data interface{}, resultChan chan map
 	}
 
 	log.Debug().Str("DAG", dag.DagInstanceName).Str("taskId", taskId).Str("taskUUID", taskUUID).Int("results", dag.numberOfFinalTasks).Msg("New task has been registred")
+
+	// Invalid options or a failed on_run_start hook fail the task: all assets are ignored
+	ignore := false
+	resolvedOptions, err := options.Resolve(time.Now())
+	if err == nil {
+		err = resolvedOptions.ResolveSelection(dag.assetsMap, dag.testsMap)
+	}
+	if err != nil {
+		log.Error().
+			Str("DAG", dag.DagInstanceName).
+			Str("taskId", taskId).
+			Str("taskUUID", taskUUID).
+			Err(err).
+			Msg("Invalid run options")
+		ignore = true
+		// The ignored task still reaches the on_run_end hook with the defaults
+		resolvedOptions, _ = (*processing.RunOptions)(nil).Resolve(time.Now())
+	} else if err = dag.runHooks.Execute(dag.hookContext(taskId, taskUUID, resolvedOptions), processing.HOOK_ON_RUN_START); err != nil {
+		log.Error().Caller().Stack().
+			Str("DAG", dag.DagInstanceName).
+			Str("taskId", taskId).
+			Str("taskUUID", taskUUID).
+			Err(err).
+			Msg("Hook Error")
+		ignore = true
+	}
 	for _, assetName := range dag.dagGrpah[0] {
 		routine := dag.dagRoutineMap[assetName]
-		dag.propagateTask(taskId, taskUUID, "", false, false, routine.InputChannels, data)
+		dag.propagateTask(taskId, taskUUID, "", false, ignore, routine.InputChannels, data, resolvedOptions)
 	}
 	return resultChan
 }
 
+// SetRunHooks implements DAG.
+func (dag *ChannelDag) SetRunHooks(hooks *processing.RunHooks) {
+	dag.runHooks = hooks
+}
+
+func (dag *ChannelDag) hookContext(taskId string, taskUUID string, options *processing.RunOptions) *processing.TaskContext {
+	return &processing.TaskContext{
+		TaskID:        taskId,
+		TaskUUID:      taskUUID,
+		InstanceName:  dag.DagInstanceName,
+		InstanceUUID:  dag.DagInstanceUUID,
+		Vars:          options.Vars,
+		RunDate:       options.RunDate,
+		IntervalStart: options.IntervalStart,
+		IntervalEnd:   options.IntervalEnd,
+	}
+}
+
 func (dag *ChannelDag) Stop() {
 	log.Debug().Str("DAG", dag.DagInstanceName).Str("taskId", STOP_TASK_ID).Int("results", dag.numberOfFinalTasks).Msg("Stop task has been registred")
 
 	for _, assetName := range dag.dagGrpah[0] {
 		routine := dag.dagRoutineMap[assetName]
-		dag.propagateTask(STOP_TASK_ID, "", "", true, false, routine.InputChannels, nil)
+		dag.propagateTask(STOP_TASK_ID, "", "", true, false, routine.InputChannels, nil, nil)
 	}
 }
 
@@ -192,6 +244,7 @@ func (routine *DagRoutine) run(wg *sync.WaitGroup) {
 		var ignore bool
 		var taskId string
 		var taskUUID string
+		var options *processing.RunOptions
 		for channelName, inputChannel := range routine.InputChannels {
 			inputTask := <-inputChannel
 			log.Debug().
@@ -200,7 +253,7 @@ func (routine *DagRoutine) run(wg *sync.WaitGroup) {
 				Str("assetName", routine.Name).
 				Str("taskId", inputTask.TaskID).Msg("task received")
 			if inputTask.StopSignal {
-				routine.dag.propagateTask(inputTask.TaskID, inputTask.TaskUUID, routine.Name, true, true, routine.OutPutChannels, nil)
+				routine.dag.propagateTask(inputTask.TaskID, inputTask.TaskUUID, routine.Name, true, true, routine.OutPutChannels, nil, nil)
 				log.Debug().
 					Str("DAG", routine.dag.DagInstanceName).
 					Str("channelName", channelName).
@@ -214,6 +267,18 @@ func (routine *DagRoutine) run(wg *sync.WaitGroup) {
 			params[channelName] = inputTask.Data
 			taskId = inputTask.TaskID
 			taskUUID = inputTask.TaskUUID
+			options = inputTask.Options
+		}
+
+		// Unselected assets are not executed, their downstreams run without their data
+		if !ignore && !options.IsSelected(routine.Name) {
+			log.Info().
+				Str("DAG", routine.dag.DagInstanceName).
+				Str("assetName", routine.Name).
+				Str("taskId", taskId).
+				Msg("Asset skipped")
+			routine.dag.propagateTask(taskId, taskUUID, routine.Name, false, false, routine.OutPutChannels, nil, options)
+			continue
 		}
 
 		if !ignore {
@@ -225,13 +290,19 @@ func (routine *DagRoutine) run(wg *sync.WaitGroup) {
 			startTaskTs := time.Now().UnixMilli()
 			// Create TaskContext
 			ctx := &processing.TaskContext{
-				TaskID:       taskId,
-				TaskUUID:     taskUUID,
-				InstanceName: routine.dag.DagInstanceName,
-				InstanceUUID: routine.dag.DagInstanceUUID,
-				Input:        params,
+				TaskID:        taskId,
+				TaskUUID:      taskUUID,
+				InstanceName:  routine.dag.DagInstanceName,
+				InstanceUUID:  routine.dag.DagInstanceUUID,
+				Input:         params,
+				Vars:          options.Vars,
+				RunDate:       options.RunDate,
+				IntervalStart: options.IntervalStart,
+				IntervalEnd:   options.IntervalEnd,
+				FullRefresh:   options.IsFullRefresh(routine.Name),
+				SkippedTests:  options.SkippedTests,
 			}
-			outputData, err := routine.Asset.Execute(ctx)
+			outputData, attempts, err := processing.ExecuteWithRetries(ctx, routine.Asset, nil)
 			stopTaskTs := time.Now().UnixMilli()
 			if err != nil {
 				log.Error().Caller().Stack().
@@ -239,9 +310,10 @@ func (routine *DagRoutine) run(wg *sync.WaitGroup) {
 					Str("assetName", routine.Name).
 					Str("taskId", taskId).
 					Float64("durationSec", float64(stopTaskTs-startTaskTs)/1000.0).
+					Int("attempts", attempts).
 					Err(err).
 					Msg("Asset Error")
-				routine.dag.propagateTask(taskId, taskUUID, routine.Name, false, true, routine.OutPutChannels, nil)
+				routine.dag.propagateTask(taskId, taskUUID, routine.Name, false, true, routine.OutPutChannels, nil, options)
 			} else {
 				if outputData != nil {
 					log.Debug().
@@ -251,15 +323,19 @@ func (routine *DagRoutine) run(wg *sync.WaitGroup) {
 						Msgf("Complete with data: %v", outputData)
 				}
 				testResults := routine.Asset.RunTests(ctx, routine.testsMap)
+				blocked := processing.BlocksDownstream(routine.Asset, testResults)
 
 				// Log test results if any tests were run
 				if len(testResults) > 0 {
 					passed := 0
+					warned := 0
 					failed := 0
 					for _, result := range testResults {
 						switch result.Status {
 						case processing.TestStatusSuccess:
 							passed++
+						case processing.TestStatusWarn:
+							warned++
 						case processing.TestStatusFailed:
 							failed++
 						}
@@ -270,18 +346,41 @@ func (routine *DagRoutine) run(wg *sync.WaitGroup) {
 						Str("taskId", taskId).
 						Int("totalTests", len(testResults)).
 						Int("passed", passed).
+						Int("warned", warned).
 						Int("failed", failed).
 						Msg("Tests executed")
+					if failed > 0 {
+						log.Warn().
+							Str("DAG", routine.dag.DagInstanceName).
+							Str("assetName", routine.Name).
+							Str("taskId", taskId).
+							Str("state", string(NodeStateTestsFailed)).
+							Int("failed", failed).
+							Bool("blockDownstream", blocked).
+							Msg("Some tests failed")
+					}
 				}
 
 				stopTaskTs := time.Now().UnixMilli()
+				if blocked {
+					// The asset is failed by its tests, the downstreams are ignored like after an asset error
+					log.Error().
+						Str("DAG", routine.dag.DagInstanceName).
+						Str("assetName", routine.Name).
+						Str("taskId", taskId).
+						Str("state", string(NodeStateTestsFailed)).
+						Float64("durationSec", float64(stopTaskTs-startTaskTs)/1000.0).
+						Msg("Asset failed by tests")
+					routine.dag.propagateTask(taskId, taskUUID, routine.Name, false, true, routine.OutPutChannels, nil, options)
+					continue
+				}
 				log.Info().
 					Str("DAG", routine.dag.DagInstanceName).
 					Str("assetName", routine.Name).
 					Str("taskId", taskId).
 					Float64("durationSec", float64(stopTaskTs-startTaskTs)/1000.0).
 					Msg("Asset complete")
-				routine.dag.propagateTask(taskId, taskUUID, routine.Name, false, false, routine.OutPutChannels, outputData)
+				routine.dag.propagateTask(taskId, taskUUID, routine.Name, false, false, routine.OutPutChannels, outputData, options)
 			}
 		} else {
 			log.Warn().
@@ -289,13 +388,13 @@ func (routine *DagRoutine) run(wg *sync.WaitGroup) {
 				Str("assetName", routine.Name).
 				Str("taskId", taskId).
 				Msg("Task has been ingored")
-			routine.dag.propagateTask(taskId, taskUUID, routine.Name, false, true, routine.OutPutChannels, nil)
+			routine.dag.propagateTask(taskId, taskUUID, routine.Name, false, true, routine.OutPutChannels, nil, options)
 		}
 	}
 
 }
 
-func (dag *ChannelDag) propagateTask(taskId string, taskUUID string, assetName string, stop bool, ingore bool, channels map[string]chan *TransitionTask, data interface{}) {
+func (dag *ChannelDag) propagateTask(taskId string, taskUUID string, assetName string, stop bool, ingore bool, channels map[string]chan *TransitionTask, data interface{}, options *processing.RunOptions) {
 
 	if channels == nil {
 		log.Debug().
@@ -330,24 +429,41 @@ func (dag *ChannelDag) propagateTask(taskId string, taskUUID string, assetName s
 				if dag.testsMap != nil {
 					log.Info().Str("taskId", taskId).Str("taskUUID", taskUUID).Msg("Executing root tests")
 					rootCtx := &processing.TaskContext{
-						TaskID:       taskId,
-						TaskUUID:     taskUUID,
-						InstanceName: dag.DagInstanceName,
-						InstanceUUID: dag.DagInstanceUUID,
+						TaskID:        taskId,
+						TaskUUID:      taskUUID,
+						InstanceName:  dag.DagInstanceName,
+						InstanceUUID:  dag.DagInstanceUUID,
+						Vars:          options.Vars,
+						RunDate:       options.RunDate,
+						IntervalStart: options.IntervalStart,
+						IntervalEnd:   options.IntervalEnd,
 					}
 					for testName, testCase := range dag.testsMap {
 						// Only run tests with "root." prefix
-						if len(testName) >= 5 && testName[:5] == "root." {
+						if len(testName) >= 5 && testName[:5] == "root." && !options.SkippedTests[testName] {
 							status, executedTestName, err := testCase.Execute(rootCtx)
-							if status {
+							switch status {
+							case processing.TestStatusSuccess:
 								log.Info().Str("taskId", taskId).Str("taskUUID", taskUUID).Str("testName", executedTestName).Msg("Root test passed")
-							} else {
+							case processing.TestStatusWarn:
+								log.Warn().Str("taskId", taskId).Str("taskUUID", taskUUID).Str("testName", executedTestName).Err(err).Msg("Root test warned")
+							default:
 								log.Error().Str("taskId", taskId).Str("taskUUID", taskUUID).Str("testName", executedTestName).Err(err).Msg("Root test failed")
 							}
 						}
 					}
 				}
 
+				err := dag.runHooks.Execute(dag.hookContext(taskId, taskUUID, options), processing.HOOK_ON_RUN_END)
+				if err != nil {
+					log.Error().Caller().Stack().
+						Str("DAG", dag.DagInstanceName).
+						Str("taskId", taskId).
+						Str("taskUUID", taskUUID).
+						Err(err).
+						Msg("Hook Error")
+				}
+
 				resultTask.resultChan <- resultTask.results
 
 				dag.mu.Lock()
@@ -365,6 +481,7 @@ func (dag *ChannelDag) propagateTask(taskId string, taskUUID string, assetName s
 			StopSignal:   stop,
 			IngoreSignal: ingore,
 			Data:         data,
+			Options:      options,
 		}
 		if stop {
 			close(output)
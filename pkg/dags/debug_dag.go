@@ -1,6 +1,7 @@
 package dags
 
 import (
+	"fmt"
 	"sync"
 	"time"
 
@@ -32,6 +33,7 @@ type DagAssetDebugService struct {
 	Downstreams           []*DagAssetDebugService // Pointers to downstream assets
 	State                 NodeState
 	TestsPassed           int
+	TestsWarned           int // Tests with severity warn or over the warn_if threshold
 	TestsFailed           int
 	Tests                 map[string]processing.ModelTesting
 	TestResults           []processing.TestResult // Store test execution results
@@ -39,6 +41,7 @@ type DagAssetDebugService struct {
 	LastResult            interface{}
 	LastExecutionDuration int64      // Duration in milliseconds
 	LastTestsDuration     int64      // Duration of tests execution in milliseconds
+	Attempts              int        // Number of execution attempts of the last run, more than 1 after retries
 	StartTime             *time.Time // Start time of execution
 	EndTime               *time.Time // End time of execution
 }
@@ -67,6 +70,7 @@ type DebugDag struct {
 	TaskUUIDMap      map[string]string                          // Map of taskId to taskUUID
 	TestExecutionMap map[string]map[string]*TestExecutionResult // Map of taskId -> testName -> result
 	isConnected      bool                                       // Track database connection status
+	RunHooks         *processing.RunHooks                       // on_run_start and on_run_end hooks
 
 	// mu protects short read/write windows on shared state: NodeMap entries
 	// (per-node mutable fields), TaskUUIDMap, TestExecutionMap, RootTestResults,
@@ -147,6 +151,14 @@ func (d *DebugDag) build() {
 							}
 						}
 					}
+				case *models.SeedDescriptor:
+					if desc.ModelProfile != nil && desc.ModelProfile.Tests != nil {
+						for _, testProfile := range desc.ModelProfile.Tests {
+							if test, exists := d.TestsMap[testProfile.Name]; exists {
+								node.Tests[testProfile.Name] = test
+							}
+						}
+					}
 				}
 			}
 
@@ -200,6 +212,11 @@ func (d *DebugDag) Run() *sync.WaitGroup {
 
 // Push implements DAG.Push - Executes assets sequentially according to dagGraph
 func (d *DebugDag) Push(taskId string, data interface{}, resultChan chan map[string]interface{}) chan map[string]interface{} {
+	return d.PushWithOptions(taskId, data, nil, resultChan)
+}
+
+// PushWithOptions implements DAG.PushWithOptions
+func (d *DebugDag) PushWithOptions(taskId string, data interface{}, options *processing.RunOptions, resultChan chan map[string]interface{}) chan map[string]interface{} {
 	taskUUID := uuid.New().String()
 	log.Info().Str("taskId", taskId).Str("taskUUID", taskUUID).Msg("DebugDag.Push() starting sequential execution")
 
@@ -225,7 +242,9 @@ func (d *DebugDag) Push(taskId string, data interface{}, resultChan chan map[str
 			node.LastError = nil
 			node.LastExecutionDuration = 0
 			node.LastTestsDuration = 0
+			node.Attempts = 0
 			node.TestsPassed = 0
+			node.TestsWarned = 0
 			node.TestsFailed = 0
 			node.TestResults = nil
 			node.StartTime = nil
@@ -238,6 +257,57 @@ func (d *DebugDag) Push(taskId string, data interface{}, resultChan chan map[str
 		}
 		d.mu.Unlock()
 
+		resolvedOptions, err := options.Resolve(time.Now())
+		if err == nil {
+			err = resolvedOptions.ResolveSelection(d.AssetsMap, d.TestsMap)
+		}
+		if err != nil {
+			log.Error().
+				Str("taskId", taskId).
+				Str("taskUUID", taskUUID).
+				Err(err).
+				Msg("Invalid run options")
+			resolvedOptions, _ = (*processing.RunOptions)(nil).Resolve(time.Now())
+		}
+		hookCtx := &processing.TaskContext{
+			TaskID:        taskId,
+			TaskUUID:      taskUUID,
+			InstanceName:  d.DagInstanceName,
+			InstanceUUID:  d.DagInstanceUUID,
+			Vars:          resolvedOptions.Vars,
+			RunDate:       resolvedOptions.RunDate,
+			IntervalStart: resolvedOptions.IntervalStart,
+			IntervalEnd:   resolvedOptions.IntervalEnd,
+		}
+		if err == nil {
+			if err = d.RunHooks.Execute(hookCtx, processing.HOOK_ON_RUN_START); err != nil {
+				log.Error().Caller().
+					Str("taskId", taskId).
+					Str("taskUUID", taskUUID).
+					Err(err).
+					Msg("Hook Error")
+			}
+		}
+
+		// Invalid options or a failed on_run_start hook fail every asset of the task
+		if err != nil {
+			d.mu.Lock()
+			for _, node := range d.NodeMap {
+				node.State = NodeStateFailed
+				node.LastError = err
+			}
+			d.mu.Unlock()
+			select {
+			case resultChan <- map[string]interface{}{}:
+			default:
+				log.Warn().Str("taskId", taskId).Str("taskUUID", taskUUID).Msg("Result channel not ready, results not sent")
+			}
+			return
+		}
+
+		// blocked are the failed assets and the assets failed by their tests, their downstreams are ignored
+		blocked := make(map[string]bool)
+
 		// Execute assets according to dagGraph order (level by level)
 		for levelIdx, taskGroup := range d.DagGraph {
 			log.Info().Str("taskId", taskId).Str("taskUUID", taskUUID).Int("level", levelIdx).Int("tasks", len(taskGroup)).Msg("Executing DAG level")
@@ -251,6 +321,32 @@ func (d *DebugDag) Push(taskId string, data interface{}, resultChan chan map[str
 					continue
 				}
 
+				blockedBy := ""
+				for _, upstream := range node.Upstreams {
+					if blocked[upstream.Name] {
+						blockedBy = upstream.Name
+						break
+					}
+				}
+
+				// Unselected assets keep the initial state and are not executed
+				if !resolvedOptions.IsSelected(assetName) {
+					blocked[assetName] = blockedBy != ""
+					log.Info().Str("taskId", taskId).Str("taskUUID", taskUUID).Str("assetName", assetName).Msg("Asset skipped")
+					continue
+				}
+
+				// Downstreams of failed assets are ignored like in ChannelDag
+				if blockedBy != "" {
+					blocked[assetName] = true
+					d.mu.Lock()
+					node.State = NodeStateFailed
+					node.LastError = fmt.Errorf("ignored, upstream %s failed", blockedBy)
+					d.mu.Unlock()
+					log.Warn().Str("taskId", taskId).Str("taskUUID", taskUUID).Str("assetName", assetName).Str("upstream", blockedBy).Msg("Task has been ignored")
+					continue
+				}
+
 				// Snapshot upstream results into a local input map.
 				d.mu.RLock()
 				inputData := make(map[string]interface{})
@@ -272,18 +368,30 @@ func (d *DebugDag) Push(taskId string, data interface{}, resultChan chan map[str
 				d.mu.Lock()
 				node.State = NodeStateInProgress
 				node.StartTime = &startTime
+				node.Attempts = 1
 				d.mu.Unlock()
 
 				ctx := &processing.TaskContext{
-					TaskID:       taskId,
-					TaskUUID:     taskUUID,
-					InstanceName: d.DagInstanceName,
-					InstanceUUID: d.DagInstanceUUID,
-					Input:        inputData,
+					TaskID:        taskId,
+					TaskUUID:      taskUUID,
+					InstanceName:  d.DagInstanceName,
+					InstanceUUID:  d.DagInstanceUUID,
+					Input:         inputData,
+					Vars:          resolvedOptions.Vars,
+					RunDate:       resolvedOptions.RunDate,
+					IntervalStart: resolvedOptions.IntervalStart,
+					IntervalEnd:   resolvedOptions.IntervalEnd,
+					FullRefresh:   resolvedOptions.IsFullRefresh(assetName),
+					SkippedTests:  resolvedOptions.SkippedTests,
 				}
 
 				// Asset.Execute may run DB queries — do NOT hold d.mu here.
-				result, err := node.Asset.Execute(ctx)
+				result, attempts, err := processing.ExecuteWithRetries(ctx, node.Asset, func(attempt int, err error) {
+					d.mu.Lock()
+					node.Attempts = attempt
+					node.LastError = err
+					d.mu.Unlock()
+				})
 				endTime := time.Now()
 				execDuration := endTime.Sub(startTime).Milliseconds()
 
@@ -295,15 +403,18 @@ func (d *DebugDag) Push(taskId string, data interface{}, resultChan chan map[str
 					node.LastError = err
 				} else {
 					node.LastResult = result
+					node.LastError = nil
 					node.State = NodeStateSuccess
 				}
 				d.mu.Unlock()
 
 				if err != nil {
+					blocked[assetName] = true
 					log.Error().Caller().
 						Str("taskId", taskId).
 						Str("assetName", assetName).
 						Int64("durationMs", execDuration).
+						Int("attempts", attempts).
 						Err(err).
 						Msg("Asset execution failed")
 					continue
@@ -313,6 +424,7 @@ func (d *DebugDag) Push(taskId string, data interface{}, resultChan chan map[str
 					Str("taskId", taskId).
 					Str("assetName", assetName).
 					Int64("durationMs", execDuration).
+					Int("attempts", attempts).
 					Msg("Asset executed successfully")
 
 				// Run tests if configured. RunTests may run DB queries —
@@ -321,6 +433,7 @@ func (d *DebugDag) Push(taskId string, data interface{}, resultChan chan map[str
 					d.mu.Lock()
 					node.State = NodeStateTesting
 					node.TestsPassed = 0
+					node.TestsWarned = 0
 					node.TestsFailed = 0
 					d.mu.Unlock()
 
@@ -332,6 +445,7 @@ func (d *DebugDag) Push(taskId string, data interface{}, resultChan chan map[str
 					testDuration := testEndTime.Sub(testStartTime).Milliseconds()
 
 					passed := 0
+					warned := 0
 					failed := 0
 					for _, testResult := range testResults {
 						switch testResult.Status {
@@ -344,6 +458,16 @@ func (d *DebugDag) Push(taskId string, data interface{}, resultChan chan map[str
 								Str("testName", testResult.TestName).
 								Int64("durationMs", testResult.DurationMs).
 								Msg("Test passed")
+						case processing.TestStatusWarn:
+							warned++
+							log.Warn().
+								Str("taskId", taskId).
+								Str("taskUUID", taskUUID).
+								Str("assetName", assetName).
+								Str("testName", testResult.TestName).
+								Err(testResult.Error).
+								Int64("durationMs", testResult.DurationMs).
+								Msg("Test warned")
 						case processing.TestStatusFailed:
 							failed++
 							log.Warn().
@@ -368,6 +492,7 @@ func (d *DebugDag) Push(taskId string, data interface{}, resultChan chan map[str
 					d.mu.Lock()
 					node.TestResults = testResults
 					node.TestsPassed = passed
+					node.TestsWarned = warned
 					node.TestsFailed = failed
 					node.LastTestsDuration = testDuration
 					if failed > 0 {
@@ -376,6 +501,7 @@ func (d *DebugDag) Push(taskId string, data interface{}, resultChan chan map[str
 						node.State = NodeStateSuccess
 					}
 					d.mu.Unlock()
+					blocked[assetName] = processing.BlocksDownstream(node.Asset, testResults)
 
 					if failed > 0 {
 						var failedTestNames []string
@@ -387,9 +513,12 @@ func (d *DebugDag) Push(taskId string, data interface{}, resultChan chan map[str
 						log.Warn().
 							Str("taskId", taskId).
 							Str("assetName", assetName).
+							Str("state", string(NodeStateTestsFailed)).
 							Int("failed", failed).
+							Int("warned", warned).
 							Int("passed", passed).
 							Strs("failedTests", failedTestNames).
+							Bool("blockDownstream", blocked[assetName]).
 							Int64("testDurationMs", testDuration).
 							Msg("Some tests failed")
 					} else {
@@ -397,6 +526,7 @@ func (d *DebugDag) Push(taskId string, data interface{}, resultChan chan map[str
 							Str("taskId", taskId).
 							Str("assetName", assetName).
 							Int("passed", passed).
+							Int("warned", warned).
 							Int64("testDurationMs", testDuration).
 							Msg("All tests passed")
 					}
@@ -424,12 +554,16 @@ func (d *DebugDag) Push(taskId string, data interface{}, resultChan chan map[str
 
 			for testName, testCase := range d.TestsMap {
 				// Only run tests with "root." prefix
-				if len(testName) >= 5 && testName[:5] == "root." {
+				if len(testName) >= 5 && testName[:5] == "root." && !resolvedOptions.SkippedTests[testName] {
 					rootCtx := &processing.TaskContext{
-						TaskID:       taskId,
-						TaskUUID:     taskUUID,
-						InstanceName: d.DagInstanceName,
-						InstanceUUID: d.DagInstanceUUID,
+						TaskID:        taskId,
+						TaskUUID:      taskUUID,
+						InstanceName:  d.DagInstanceName,
+						InstanceUUID:  d.DagInstanceUUID,
+						Vars:          resolvedOptions.Vars,
+						RunDate:       resolvedOptions.RunDate,
+						IntervalStart: resolvedOptions.IntervalStart,
+						IntervalEnd:   resolvedOptions.IntervalEnd,
 					}
 					startTime := time.Now()
 					// testCase.Execute runs DB queries — no lock held.
@@ -441,11 +575,17 @@ func (d *DebugDag) Push(taskId string, data interface{}, resultChan chan map[str
 						DurationMs: duration,
 					}
 
-					if status {
+					switch status {
+					case processing.TestStatusSuccess:
 						testResult.Status = processing.TestStatusSuccess
 						testResult.Message = "Root test passed"
 						log.Info().Str("taskId", taskId).Str("taskUUID", taskUUID).Str("testName", executedTestName).Int64("durationMs", duration).Msg("Root test passed")
-					} else {
+					case processing.TestStatusWarn:
+						testResult.Status = processing.TestStatusWarn
+						testResult.Error = err
+						testResult.Message = "Root test warned"
+						log.Warn().Str("taskId", taskId).Str("taskUUID", taskUUID).Str("testName", executedTestName).Int64("durationMs", duration).Err(err).Msg("Root test warned")
+					default:
 						testResult.Status = processing.TestStatusFailed
 						testResult.Error = err
 						testResult.Message = "Root test failed"
@@ -459,6 +599,14 @@ func (d *DebugDag) Push(taskId string, data interface{}, resultChan chan map[str
 			}
 		}
 
+		if err := d.RunHooks.Execute(hookCtx, processing.HOOK_ON_RUN_END); err != nil {
+			log.Error().Caller().
+				Str("taskId", taskId).
+				Str("taskUUID", taskUUID).
+				Err(err).
+				Msg("Hook Error")
+		}
+
 		// Send results back through the channel
 		select {
 		case resultChan <- finalResults:
@@ -476,6 +624,11 @@ func (d *DebugDag) Stop() {
 	log.Debug().Msg("DebugDag stopped")
 }
 
+// SetRunHooks implements DAG.SetRunHooks
+func (d *DebugDag) SetRunHooks(hooks *processing.RunHooks) {
+	d.RunHooks = hooks
+}
+
 // GetTaskUUID returns the UUID for a given taskId
 func (d *DebugDag) GetTaskUUID(taskId string) string {
 	d.mu.RLock()
@@ -634,9 +787,11 @@ func (d *DebugDag) GetTestResult(taskId, testName string) *TestExecutionResult {
 type NodeRuntimeState struct {
 	State                 NodeState
 	TestsPassed           int
+	TestsWarned           int
 	TestsFailed           int
 	LastExecutionDuration int64
 	LastTestsDuration     int64
+	Attempts              int
 	TestResults           []processing.TestResult
 }
 
@@ -652,9 +807,11 @@ func (d *DebugDag) NodeRuntimeStateFor(name string) (NodeRuntimeState, bool) {
 	snap := NodeRuntimeState{
 		State:                 n.State,
 		TestsPassed:           n.TestsPassed,
+		TestsWarned:           n.TestsWarned,
 		TestsFailed:           n.TestsFailed,
 		LastExecutionDuration: n.LastExecutionDuration,
 		LastTestsDuration:     n.LastTestsDuration,
+		Attempts:              n.Attempts,
 	}
 	if len(n.TestResults) > 0 {
 		snap.TestResults = make([]processing.TestResult, len(n.TestResults))
@@ -688,6 +845,7 @@ func (d *DebugDag) ResetAllNodes() {
 		node.LastError = nil
 		node.LastExecutionDuration = 0
 		node.LastTestsDuration = 0
+		node.Attempts = 0
 		node.TestsPassed = 0
 		node.TestsFailed = 0
 		node.TestResults = nil
@@ -1,9 +1,17 @@
 package dags
 
-import "sync"
+import (
+	"sync"
+
+	"github.com/go-teal/teal/pkg/processing"
+)
 
 type DAG interface {
 	Run() *sync.WaitGroup
 	Push(taskId string, data interface{}, resultChan chan map[string]interface{}) chan map[string]interface{}
+	// PushWithOptions is Push with the options of the task, e.g. the vars overriding profile.yaml
+	PushWithOptions(taskId string, data interface{}, options *processing.RunOptions, resultChan chan map[string]interface{}) chan map[string]interface{}
 	Stop()
+	// SetRunHooks sets the on_run_start and on_run_end hooks executed around every task
+	SetRunHooks(hooks *processing.RunHooks)
 }
@@ -1,10 +1,19 @@
 package drivers
 
 import (
+	"time"
+
 	"github.com/go-teal/gota/dataframe"
 	"github.com/go-teal/teal/pkg/configs"
 )
 
+// ColumnInfo describes a column of a table or of a query result.
+type ColumnInfo struct {
+	Name     string
+	Type     string
+	Nullable bool
+}
+
 type DBDriver interface {
 	Connect() error
 	Begin() (interface{}, error)
@@ -21,8 +30,40 @@ type DBDriver interface {
 	// idempotent and safe to call concurrently - several DAG nodes of the same
 	// stage can discover the same missing schema at once.
 	CreateSchema(tx interface{}, schemaName string) error
+	// GetRelationComment returns the comment of a table or a view, or an empty
+	// string if the relation has no comment.
+	GetRelationComment(tx interface{}, relationName string) (string, error)
+	// GetTableColumns returns the columns of a table in their ordinal order.
+	GetTableColumns(tx interface{}, tableName string) ([]ColumnInfo, error)
+	// GetQueryColumns returns the columns the query produces, without fetching
+	// any rows. Types are reported in the same notation as GetTableColumns.
+	GetQueryColumns(tx interface{}, sqlQuery string) ([]ColumnInfo, error)
+	// GetMaxTimestamp returns the greatest value of a timestamp column of the table,
+	// or nil if the table has no rows.
+	GetMaxTimestamp(tx interface{}, tableName string, columnName string) (*time.Time, error)
+	// CountRows returns the number of rows of the query.
+	CountRows(tx interface{}, sqlQuery string) (int64, error)
+	// ExportQuery writes the result of the query to a file (see configs.EXPORT_*), or with partitionBy
+	// to the <column>=<value> subdirectories of path. Returns the paths of the written files.
+	ExportQuery(tx interface{}, sqlQuery string, path string, format string, partitionBy []string) ([]string, error)
+	// LoadSeed replaces tableName with the content of a seed file (see SEED_FORMAT_*).
+	// columnTypes overrides the inferred types of the listed columns.
+	LoadSeed(tx interface{}, tableName string, filePath string, format string, columnTypes map[string]string) error
+	// CreatePartitionedTable creates an empty partitioned table with the columns of the query.
+	CreatePartitionedTable(tx interface{}, tableName string, sqlQuery string, partitionBy *configs.PartitionProfile) error
+	// InsertPartitions inserts the rows of the query into a partitioned table, creating the
+	// missing partitions. With replace the partitions of the rows are truncated first.
+	// Returns the names of the partitions the rows fall into.
+	InsertPartitions(tx interface{}, tableName string, sqlQuery string, partitionBy *configs.PartitionProfile, replace bool) ([]string, error)
+	// ApplyGrants grants the privileges (privilege -> roles) on a table or a view.
+	// Databases without roles ignore the grants.
+	ApplyGrants(tx interface{}, relationName string, grants map[string][]string) error
+	// IsRetryableError reports whether the error is transient (serialization failures,
+	// deadlocks, lock timeouts, write conflicts) and the operation can be executed again.
+	IsRetryableError(err error) bool
 	GetRawConnection() interface{}
-	SimpleTest(sql string) (string, error)
+	// SimpleTest executes the count query of a test and returns the number of failing rows.
+	SimpleTest(sql string) (int64, error)
 	ConcurrencyLock()
 	ConcurrencyUnlock()
 }
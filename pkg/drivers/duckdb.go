@@ -4,8 +4,10 @@ import (
 	"database/sql"
 	"fmt"
 	"os"
+	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/go-teal/teal/pkg/configs"
 	"github.com/rs/zerolog/log"
@@ -103,6 +105,148 @@ func (d *DuckDBEngine) CheckTableExists(tx interface{}, tableName string) bool {
 	return count > 0
 }
 
+// GetRelationComment implements DBEngine.
+func (d *DuckDBEngine) GetRelationComment(tx interface{}, relationName string) (string, error) {
+	splitted := strings.Split(relationName, ".")
+	query := `SELECT coalesce(comment, '') FROM duckdb_views() WHERE schema_name=$1 and view_name=$2
+		UNION ALL
+		SELECT coalesce(comment, '') FROM duckdb_tables() WHERE schema_name=$1 and table_name=$2;`
+	var comment string
+	err := tx.(*sql.Tx).QueryRow(query, splitted[0], splitted[1]).Scan(&comment)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return comment, err
+}
+
+// GetMaxTimestamp implements DBEngine.
+func (d *DuckDBEngine) GetMaxTimestamp(tx interface{}, tableName string, columnName string) (*time.Time, error) {
+	var maxTimestamp sql.NullTime
+	err := tx.(*sql.Tx).QueryRow(fmt.Sprintf("SELECT max(%s)::TIMESTAMPTZ FROM %s;", columnName, tableName)).Scan(&maxTimestamp)
+	if err != nil || !maxTimestamp.Valid {
+		return nil, err
+	}
+	return &maxTimestamp.Time, nil
+}
+
+// CountRows implements DBEngine.
+func (d *DuckDBEngine) CountRows(tx interface{}, sqlQuery string) (int64, error) {
+	var count int64
+	err := tx.(*sql.Tx).QueryRow(fmt.Sprintf("SELECT count(*) FROM (%s) AS teal_count_src;", sqlQuery)).Scan(&count)
+	return count, err
+}
+
+// ExportQuery implements DBEngine. The files are written by DuckDB with COPY ... TO.
+func (d *DuckDBEngine) ExportQuery(tx interface{}, sqlQuery string, path string, format string, partitionBy []string) ([]string, error) {
+	writePath, err := prepareExportPath(path, partitionBy)
+	if err != nil {
+		return nil, err
+	}
+	options := []string{"FORMAT " + format}
+	if format == configs.EXPORT_CSV {
+		options = append(options, "HEADER")
+	}
+	if len(partitionBy) > 0 {
+		options = append(options, "PARTITION_BY ("+strings.Join(partitionBy, ", ")+")")
+	}
+	query := fmt.Sprintf("COPY (%s) TO '%s' (%s);",
+		strings.TrimSuffix(strings.TrimSpace(sqlQuery), ";"),
+		strings.ReplaceAll(writePath, "'", "''"),
+		strings.Join(options, ", "))
+	if _, err := tx.(*sql.Tx).Exec(query); err != nil {
+		log.Error().Caller().Str("sql", query).Err(err).Msg("Failed to export")
+		abortExportPath(writePath, partitionBy)
+		return nil, err
+	}
+	return finishExportPath(path, writePath, partitionBy)
+}
+
+// GetTableColumns implements DBEngine.
+func (d *DuckDBEngine) GetTableColumns(tx interface{}, tableName string) ([]ColumnInfo, error) {
+	return describeDuckDBColumns(tx, tableName)
+}
+
+// GetQueryColumns implements DBEngine.
+func (d *DuckDBEngine) GetQueryColumns(tx interface{}, sqlQuery string) ([]ColumnInfo, error) {
+	return describeDuckDBColumns(tx, fmt.Sprintf("SELECT * FROM (%s) AS teal_probe_src", sqlQuery))
+}
+
+// describeDuckDBColumns reads the columns of a table or a query with DESCRIBE, so the columns of tables and
+// of queries are reported in the same notation (information_schema spells some types differently)
+func describeDuckDBColumns(tx interface{}, relation string) ([]ColumnInfo, error) {
+	query := fmt.Sprintf("SELECT column_name, column_type, \"null\" = 'YES' FROM (DESCRIBE %s);", relation)
+	rows, err := tx.(*sql.Tx).Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var columns []ColumnInfo
+	for rows.Next() {
+		var column ColumnInfo
+		if err := rows.Scan(&column.Name, &column.Type, &column.Nullable); err != nil {
+			return nil, err
+		}
+		columns = append(columns, column)
+	}
+	return columns, rows.Err()
+}
+
+// LoadSeed implements DBEngine. Seeds are read by DuckDB itself, the type overrides are casts.
+func (d *DuckDBEngine) LoadSeed(tx interface{}, tableName string, filePath string, format string, columnTypes map[string]string) error {
+	quotedPath := strings.ReplaceAll(filePath, "'", "''")
+	var reader string
+	switch format {
+	case SEED_FORMAT_CSV:
+		reader = fmt.Sprintf("read_csv('%s', header = true)", quotedPath)
+	case SEED_FORMAT_PARQUET:
+		reader = fmt.Sprintf("read_parquet('%s')", quotedPath)
+	case SEED_FORMAT_JSON:
+		reader = fmt.Sprintf("read_json('%s')", quotedPath)
+	default:
+		return fmt.Errorf("unknown seed format %s", format)
+	}
+
+	projection := "*"
+	if len(columnTypes) > 0 {
+		columnNames := make([]string, 0, len(columnTypes))
+		for columnName := range columnTypes {
+			columnNames = append(columnNames, columnName)
+		}
+		sort.Strings(columnNames)
+		casts := make([]string, len(columnNames))
+		for i, columnName := range columnNames {
+			casts[i] = fmt.Sprintf("cast(\"%s\" as %s) as \"%s\"", columnName, columnTypes[columnName], columnName)
+		}
+		projection = fmt.Sprintf("* replace (%s)", strings.Join(casts, ", "))
+	}
+
+	return d.Exec(tx, fmt.Sprintf("drop table if exists %s; create table %s as select %s from %s;", tableName, tableName, projection, reader))
+}
+
+// CreatePartitionedTable implements DBEngine. DuckDB has no declarative partitioning.
+func (d *DuckDBEngine) CreatePartitionedTable(tx interface{}, tableName string, sqlQuery string, partitionBy *configs.PartitionProfile) error {
+	return fmt.Errorf("partitioned tables are not supported by DuckDB")
+}
+
+// InsertPartitions implements DBEngine. DuckDB has no declarative partitioning.
+func (d *DuckDBEngine) InsertPartitions(tx interface{}, tableName string, sqlQuery string, partitionBy *configs.PartitionProfile, replace bool) ([]string, error) {
+	return nil, fmt.Errorf("partitioned tables are not supported by DuckDB")
+}
+
+// ApplyGrants implements DBEngine. DuckDB has no roles, the grants are ignored.
+func (d *DuckDBEngine) ApplyGrants(tx interface{}, relationName string, grants map[string][]string) error {
+	if len(grants) > 0 {
+		log.Warn().Str("relation", relationName).Msg("DuckDB does not support grants, skipping")
+	}
+	return nil
+}
+
+// IsRetryableError implements DBEngine. Write-write conflicts and file locks of other processes are retryable.
+func (d *DuckDBEngine) IsRetryableError(err error) bool {
+	return isRetryableDuckDBError(err)
+}
+
 // Close implements DBEngine.
 func (d *DuckDBEngine) Close() error {
 	log.Debug().Str("path", d.dbConnection.Config.Path).Msg("disconnected")
@@ -0,0 +1,55 @@
+package drivers
+
+import (
+	"database/sql"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+
+	"github.com/go-teal/teal/pkg/configs"
+)
+
+// Needs the duckdb database/sql driver, see duckdb_driver_test.go
+func newTestDuckDBEngine(t *testing.T) *DuckDBEngine {
+	t.Helper()
+	if !slices.Contains(sql.Drivers(), "duckdb") {
+		t.Skip("the duckdb driver is not registered, skipping the live DuckDB test")
+	}
+	// An in-memory database
+	var connectionConfig configs.DBConnectionConfig
+	require.NoError(t, yaml.Unmarshal([]byte("name: test\ntype: duckdb\nconfig:\n  path: ''\n"), &connectionConfig))
+	engine := &DuckDBEngine{dbConnection: &connectionConfig}
+	require.NoError(t, engine.Connect())
+	t.Cleanup(func() { engine.Close() })
+	return engine
+}
+
+func TestDuckDBUnchangedSchemaReportsSameColumns(t *testing.T) {
+	engine := newTestDuckDBEngine(t)
+	modelSQL := `select cast(1 as integer) as id, cast(1.5 as decimal(18, 3)) as amount, 'a' as name,
+		now() as created_at, cast(now() as timestamp) as loaded_at, [1, 2] as tags,
+		{'code': 'AMS'} as airport, cast('x' as blob) as payload, true as active`
+
+	tx, err := engine.Begin()
+	require.NoError(t, err)
+	defer engine.Rollback(tx)
+	require.NoError(t, engine.Exec(tx, "create schema dds"))
+	require.NoError(t, engine.Exec(tx, "create table dds.orders as ("+modelSQL+")"))
+
+	tableColumns, err := engine.GetTableColumns(tx, "dds.orders")
+	require.NoError(t, err)
+	queryColumns, err := engine.GetQueryColumns(tx, modelSQL)
+	require.NoError(t, err)
+
+	// The schema change check compares the types case-insensitively, an unchanged model must report no change
+	require.Len(t, queryColumns, len(tableColumns))
+	for i, column := range tableColumns {
+		assert.Equal(t, column.Name, queryColumns[i].Name)
+		assert.True(t, strings.EqualFold(column.Type, queryColumns[i].Type),
+			"column %s: table type %s, query type %s", column.Name, column.Type, queryColumns[i].Type)
+	}
+}
@@ -0,0 +1,9 @@
+//go:build duckdb
+
+package drivers
+
+// Registers the duckdb database/sql driver for the live DuckDB tests:
+//
+//	go get github.com/marcboeker/go-duckdb/v2
+//	go test -tags duckdb ./pkg/drivers/ -run DuckDB
+import _ "github.com/marcboeker/go-duckdb/v2"
@@ -2,13 +2,13 @@ package drivers
 
 import "database/sql"
 
-func (d *DuckDBEngine) SimpleTest(sqlQuery string) (string, error) {
-	var count sql.NullString
+func (d *DuckDBEngine) SimpleTest(sqlQuery string) (int64, error) {
+	var count sql.NullInt64
 	err := d.db.QueryRow(sqlQuery).Scan(&count)
 
 	if err == sql.ErrNoRows {
-		return "", nil
+		return 0, nil
 	}
 
-	return count.String, err
+	return count.Int64, err
 }
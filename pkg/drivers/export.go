@@ -0,0 +1,305 @@
+package drivers
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-teal/teal/pkg/configs"
+)
+
+// EXPORT_MARKER_FILE marks the directories written by partitioned exports, only they are replaced by the next export
+const EXPORT_MARKER_FILE = ".teal_export"
+
+// prepareExportPath creates the parent directory of an export file and returns the path to write to.
+// A partitioned export is written to a sibling staging directory, which replaces the export directory
+// in finishExportPath, so that the directory holds the files of this export only. An existing export
+// directory is replaced only if it was written by teal (it has the marker file) or is empty.
+func prepareExportPath(path string, partitionBy []string) (string, error) {
+	if len(partitionBy) == 0 {
+		return path, os.MkdirAll(filepath.Dir(path), 0755)
+	}
+	cleanPath := filepath.Clean(path)
+	if path == "" || cleanPath == "." || cleanPath == ".." || cleanPath == filepath.Dir(cleanPath) {
+		return "", fmt.Errorf("refusing to export to %q, the path of a partitioned export must name its own directory", path)
+	}
+	if err := checkExportDir(cleanPath); err != nil {
+		return "", err
+	}
+	stagingPath := exportStagingPath(cleanPath)
+	// The staging directory is named by teal and only holds an unfinished export
+	if err := os.RemoveAll(stagingPath); err != nil {
+		return "", err
+	}
+	return stagingPath, os.MkdirAll(filepath.Dir(cleanPath), 0755)
+}
+
+// finishExportPath moves a partitioned export from the staging directory into place
+// and returns the written files in lexical order
+func finishExportPath(path string, writtenPath string, partitionBy []string) ([]string, error) {
+	if len(partitionBy) == 0 {
+		return []string{path}, nil
+	}
+	cleanPath := filepath.Clean(path)
+	// An export without rows writes no files
+	if err := os.MkdirAll(writtenPath, 0755); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(writtenPath, EXPORT_MARKER_FILE), nil, 0644); err != nil {
+		return nil, err
+	}
+	if err := checkExportDir(cleanPath); err != nil {
+		return nil, err
+	}
+	if err := os.RemoveAll(cleanPath); err != nil {
+		return nil, err
+	}
+	if err := os.Rename(writtenPath, cleanPath); err != nil {
+		return nil, err
+	}
+	return listExportFiles(cleanPath, partitionBy)
+}
+
+// abortExportPath removes the staging directory of a failed partitioned export
+func abortExportPath(writtenPath string, partitionBy []string) {
+	if len(partitionBy) > 0 {
+		os.RemoveAll(writtenPath)
+	}
+}
+
+// checkExportDir returns an error if the directory exists and was not written by a partitioned export
+func checkExportDir(path string) error {
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("refusing to replace %s: it is not a directory", path)
+	}
+	if _, err := os.Stat(filepath.Join(path, EXPORT_MARKER_FILE)); err == nil {
+		return nil
+	}
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return err
+	}
+	if len(entries) > 0 {
+		return fmt.Errorf("refusing to replace %s: the directory was not written by a teal export (no %s file)", path, EXPORT_MARKER_FILE)
+	}
+	return nil
+}
+
+func exportStagingPath(path string) string {
+	return filepath.Join(filepath.Dir(path), "."+filepath.Base(path)+".teal_tmp")
+}
+
+// listExportFiles returns the files written by an export in lexical order
+func listExportFiles(path string, partitionBy []string) ([]string, error) {
+	if len(partitionBy) == 0 {
+		return []string{path}, nil
+	}
+	var paths []string
+	err := filepath.WalkDir(path, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !entry.IsDir() && entry.Name() != EXPORT_MARKER_FILE {
+			paths = append(paths, filePath)
+		}
+		return nil
+	})
+	sort.Strings(paths)
+	return paths, err
+}
+
+// exportWriter writes rows to the files of an export, one file per partition.
+// Partitions are <column>=<value> directories like the ones DuckDB writes with PARTITION_BY.
+type exportWriter struct {
+	path             string
+	format           string
+	columns          []string
+	partitionColumns []string
+	partitionIndexes []int
+	files            map[string]*exportFile
+}
+
+type exportFile struct {
+	file      *os.File
+	buffer    *bufio.Writer
+	csvWriter *csv.Writer
+}
+
+func newExportWriter(path string, format string, columns []string, partitionBy []string) (*exportWriter, error) {
+	if format != configs.EXPORT_CSV && format != configs.EXPORT_JSON {
+		return nil, fmt.Errorf("export format %s is not supported by this driver", format)
+	}
+	writer := &exportWriter{
+		path:             path,
+		format:           format,
+		partitionColumns: partitionBy,
+		files:            make(map[string]*exportFile),
+	}
+	partitioned := make(map[int]bool)
+	for _, column := range partitionBy {
+		index := -1
+		for i, name := range columns {
+			if name == column {
+				index = i
+				break
+			}
+		}
+		if index < 0 {
+			return nil, fmt.Errorf("partition column %s is not in the query", column)
+		}
+		writer.partitionIndexes = append(writer.partitionIndexes, index)
+		partitioned[index] = true
+	}
+	for i, column := range columns {
+		if !partitioned[i] {
+			writer.columns = append(writer.columns, column)
+		}
+	}
+	if len(writer.partitionIndexes) == 0 {
+		// An empty result still produces the file
+		if _, err := writer.getFile(path); err != nil {
+			return nil, err
+		}
+	}
+	return writer, nil
+}
+
+// Write writes a row, values are in the order of the columns of the query
+func (w *exportWriter) Write(values []any) error {
+	filePath := w.path
+	if len(w.partitionIndexes) > 0 {
+		parts := []string{w.path}
+		for i, index := range w.partitionIndexes {
+			value := "NULL"
+			if values[index] != nil {
+				value = url.PathEscape(formatExportValue(values[index]))
+			}
+			parts = append(parts, w.partitionColumns[i]+"="+value)
+		}
+		parts = append(parts, "data_0."+w.format)
+		filePath = filepath.Join(parts...)
+	}
+	file, err := w.getFile(filePath)
+	if err != nil {
+		return err
+	}
+
+	rowValues := make([]any, 0, len(w.columns))
+	for i, value := range values {
+		if !w.isPartitionIndex(i) {
+			rowValues = append(rowValues, value)
+		}
+	}
+	if file.csvWriter != nil {
+		record := make([]string, len(rowValues))
+		for i, value := range rowValues {
+			record[i] = formatExportValue(value)
+		}
+		return file.csvWriter.Write(record)
+	}
+	file.buffer.WriteByte('{')
+	for i, value := range rowValues {
+		if i > 0 {
+			file.buffer.WriteByte(',')
+		}
+		key, _ := json.Marshal(w.columns[i])
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			encoded, _ = json.Marshal(formatExportValue(value))
+		}
+		file.buffer.Write(key)
+		file.buffer.WriteByte(':')
+		file.buffer.Write(encoded)
+	}
+	_, err = file.buffer.WriteString("}\n")
+	return err
+}
+
+// Close flushes and closes the files, returns their paths in lexical order
+func (w *exportWriter) Close() ([]string, error) {
+	var firstErr error
+	paths := make([]string, 0, len(w.files))
+	for filePath, file := range w.files {
+		paths = append(paths, filePath)
+		if file.csvWriter != nil {
+			file.csvWriter.Flush()
+			if err := file.csvWriter.Error(); err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}
+		if err := file.buffer.Flush(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+		if err := file.file.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	sort.Strings(paths)
+	return paths, firstErr
+}
+
+func (w *exportWriter) getFile(filePath string) (*exportFile, error) {
+	if file, ok := w.files[filePath]; ok {
+		return file, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return nil, err
+	}
+	osFile, err := os.Create(filePath)
+	if err != nil {
+		return nil, err
+	}
+	file := &exportFile{file: osFile, buffer: bufio.NewWriter(osFile)}
+	if w.format == configs.EXPORT_CSV {
+		file.csvWriter = csv.NewWriter(file.buffer)
+		if err := file.csvWriter.Write(w.columns); err != nil {
+			osFile.Close()
+			return nil, err
+		}
+	}
+	w.files[filePath] = file
+	return file, nil
+}
+
+func (w *exportWriter) isPartitionIndex(index int) bool {
+	for _, partitionIndex := range w.partitionIndexes {
+		if partitionIndex == index {
+			return true
+		}
+	}
+	return false
+}
+
+// formatExportValue renders a value of a query result as text, NULL is an empty string
+func formatExportValue(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case json.Marshaler:
+		if encoded, err := v.MarshalJSON(); err == nil {
+			return strings.Trim(string(encoded), `"`)
+		}
+	}
+	return fmt.Sprint(value)
+}
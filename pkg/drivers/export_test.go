@@ -0,0 +1,87 @@
+package drivers
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportWriterCSV(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out", "orders.csv")
+	writePath, err := prepareExportPath(path, nil)
+	require.NoError(t, err)
+	assert.Equal(t, path, writePath)
+	writer, err := newExportWriter(path, "csv", []string{"id", "name", "loaded_at"}, nil)
+	require.NoError(t, err)
+	require.NoError(t, writer.Write([]any{int64(1), "Schiphol, NL", time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)}))
+	require.NoError(t, writer.Write([]any{int64(2), nil, nil}))
+	paths, err := writer.Close()
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{path}, paths)
+	content, _ := os.ReadFile(path)
+	assert.Equal(t, "id,name,loaded_at\n1,\"Schiphol, NL\",2024-05-01T10:00:00Z\n2,,\n", string(content))
+}
+
+func TestExportWriterPartitionedJSON(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "orders")
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "country=XX"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "country=XX", "stale.json"), nil, 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, EXPORT_MARKER_FILE), nil, 0644))
+	writePath, err := prepareExportPath(dir, []string{"country"})
+	require.NoError(t, err)
+	assert.NotEqual(t, dir, writePath, "a partitioned export is written to a staging directory")
+
+	writer, err := newExportWriter(writePath, "json", []string{"id", "country"}, []string{"country"})
+	require.NoError(t, err)
+	require.NoError(t, writer.Write([]any{int64(1), "NL"}))
+	require.NoError(t, writer.Write([]any{int64(2), "US"}))
+	require.NoError(t, writer.Write([]any{int64(3), "NL"}))
+	_, err = writer.Close()
+	require.NoError(t, err)
+	paths, err := finishExportPath(dir, writePath, []string{"country"})
+	require.NoError(t, err)
+
+	nl := filepath.Join(dir, "country=NL", "data_0.json")
+	us := filepath.Join(dir, "country=US", "data_0.json")
+	assert.Equal(t, []string{nl, us}, paths, "the files of previous exports are removed")
+	content, _ := os.ReadFile(nl)
+	assert.Equal(t, "{\"id\":1}\n{\"id\":3}\n", string(content))
+	assert.FileExists(t, filepath.Join(dir, EXPORT_MARKER_FILE))
+	assert.NoDirExists(t, writePath)
+
+	_, err = newExportWriter(dir, "json", []string{"id"}, []string{"country"})
+	assert.Error(t, err)
+	_, err = newExportWriter(dir, "parquet", []string{"id"}, nil)
+	assert.Error(t, err)
+}
+
+func TestPartitionedExportKeepsForeignDirectories(t *testing.T) {
+	root := t.TempDir()
+	project := filepath.Join(root, "project")
+	require.NoError(t, os.MkdirAll(project, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(project, "profile.yaml"), []byte("name: x"), 0644))
+
+	_, err := prepareExportPath(project, []string{"country"})
+	assert.ErrorContains(t, err, "was not written by a teal export")
+	assert.FileExists(t, filepath.Join(project, "profile.yaml"))
+
+	for _, path := range []string{"", ".", "/", root + "/.."} {
+		_, err = prepareExportPath(path, []string{"country"})
+		assert.Error(t, err, path)
+	}
+
+	// The directory appeared while the export was written
+	empty := filepath.Join(root, "empty")
+	writePath, err := prepareExportPath(empty, []string{"country"})
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(empty, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(empty, "notes.txt"), nil, 0644))
+	_, err = finishExportPath(empty, writePath, []string{"country"})
+	assert.Error(t, err)
+	assert.FileExists(t, filepath.Join(empty, "notes.txt"))
+}
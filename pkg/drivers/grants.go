@@ -0,0 +1,26 @@
+package drivers
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// grantStatements builds one GRANT statement per privilege, privileges are sorted
+// to keep the order of the statements stable
+func grantStatements(relationName string, grants map[string][]string) []string {
+	privileges := make([]string, 0, len(grants))
+	for privilege, roles := range grants {
+		if len(roles) > 0 {
+			privileges = append(privileges, privilege)
+		}
+	}
+	sort.Slice(privileges, func(i, j int) bool {
+		return strings.ToLower(privileges[i]) < strings.ToLower(privileges[j])
+	})
+	statements := make([]string, len(privileges))
+	for i, privilege := range privileges {
+		statements[i] = fmt.Sprintf("grant %s on %s to %s", strings.ToLower(privilege), relationName, strings.Join(grants[privilege], ", "))
+	}
+	return statements
+}
@@ -0,0 +1,23 @@
+package drivers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGrantStatements(t *testing.T) {
+	statements := grantStatements("dds.fact_flights", map[string][]string{
+		"SELECT": {"bi_reader", "analyst"},
+		"insert": {"loader"},
+		"delete": {},
+	})
+	assert.Equal(t, []string{
+		"grant insert on dds.fact_flights to loader",
+		"grant select on dds.fact_flights to bi_reader, analyst",
+	}, statements)
+}
+
+func TestGrantStatementsEmpty(t *testing.T) {
+	assert.Empty(t, grantStatements("dds.fact_flights", nil))
+}
@@ -0,0 +1,72 @@
+package drivers
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/go-teal/teal/pkg/configs"
+)
+
+// maxIdentifierLength is the PostgreSQL limit, longer names are truncated silently
+const maxIdentifierLength = 63
+
+var nonIdentifierChars = regexp.MustCompile(`[^a-z0-9_]+`)
+
+// partitionKeysSQL selects the distinct partition keys of the staged rows.
+// Range keys are the start dates of the partitions in the YYYY-MM-DD format.
+func partitionKeysSQL(stageTable string, partitionBy *configs.PartitionProfile) string {
+	if partitionBy.Type == configs.PARTITION_RANGE {
+		return fmt.Sprintf("select distinct to_char(date_trunc('%s', %s), 'YYYY-MM-DD') from %s where %s is not null",
+			partitionBy.Granularity, partitionBy.Column, stageTable, partitionBy.Column)
+	}
+	return fmt.Sprintf("select distinct %s::text from %s where %s is not null", partitionBy.Column, stageTable, partitionBy.Column)
+}
+
+// partitionFor returns the name of the partition holding the key and its FOR VALUES clause
+func partitionFor(tableName string, partitionBy *configs.PartitionProfile, key string) (string, string, error) {
+	if partitionBy.Type == configs.PARTITION_LIST {
+		suffix := nonIdentifierChars.ReplaceAllString(strings.ToLower(key), "_")
+		if suffix != key {
+			// Different values may sanitize to the same suffix
+			hash := md5.Sum([]byte(key))
+			suffix += "_" + hex.EncodeToString(hash[:4])
+		}
+		return partitionTableName(tableName, "p_"+suffix), fmt.Sprintf("for values in (%s)", sqlLiteral(&key)), nil
+	}
+
+	from, err := time.Parse(time.DateOnly, key)
+	if err != nil {
+		return "", "", fmt.Errorf("unexpected range partition key %q: %w", key, err)
+	}
+	var to time.Time
+	var suffix string
+	switch partitionBy.Granularity {
+	case configs.PARTITION_DAY:
+		to, suffix = from.AddDate(0, 0, 1), from.Format("20060102")
+	case configs.PARTITION_MONTH:
+		to, suffix = from.AddDate(0, 1, 0), from.Format("200601")
+	case configs.PARTITION_YEAR:
+		to, suffix = from.AddDate(1, 0, 0), from.Format("2006")
+	default:
+		return "", "", fmt.Errorf("unknown partition granularity %q", partitionBy.Granularity)
+	}
+	return partitionTableName(tableName, "p"+suffix),
+		fmt.Sprintf("for values from ('%s') to ('%s')", from.Format(time.DateOnly), to.Format(time.DateOnly)), nil
+}
+
+// partitionTableName puts the partition next to its parent table, the suffix is kept
+// when the name has to be truncated
+func partitionTableName(tableName string, suffix string) string {
+	schema, table := "", tableName
+	if i := strings.LastIndex(tableName, "."); i >= 0 {
+		schema, table = tableName[:i+1], tableName[i+1:]
+	}
+	if len(table)+1+len(suffix) > maxIdentifierLength {
+		table = table[:max(0, maxIdentifierLength-1-len(suffix))]
+	}
+	return schema + table + "_" + suffix
+}
@@ -0,0 +1,60 @@
+package drivers
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-teal/teal/pkg/configs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPartitionForRange(t *testing.T) {
+	tests := []struct {
+		granularity string
+		key         string
+		name        string
+		bounds      string
+	}{
+		{configs.PARTITION_DAY, "2024-02-28", "dds.fact_flights_p20240228", "for values from ('2024-02-28') to ('2024-02-29')"},
+		{configs.PARTITION_MONTH, "2024-12-01", "dds.fact_flights_p202412", "for values from ('2024-12-01') to ('2025-01-01')"},
+		{configs.PARTITION_YEAR, "2024-01-01", "dds.fact_flights_p2024", "for values from ('2024-01-01') to ('2025-01-01')"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.granularity, func(t *testing.T) {
+			partitionBy := &configs.PartitionProfile{Type: configs.PARTITION_RANGE, Column: "flight_date", Granularity: tt.granularity}
+			name, bounds, err := partitionFor("dds.fact_flights", partitionBy, tt.key)
+			require.NoError(t, err)
+			assert.Equal(t, tt.name, name)
+			assert.Equal(t, tt.bounds, bounds)
+		})
+	}
+}
+
+func TestPartitionForList(t *testing.T) {
+	partitionBy := &configs.PartitionProfile{Type: configs.PARTITION_LIST, Column: "region"}
+
+	name, bounds, err := partitionFor("dds.sales", partitionBy, "eu")
+	require.NoError(t, err)
+	assert.Equal(t, "dds.sales_p_eu", name)
+	assert.Equal(t, "for values in ('eu')", bounds)
+
+	name, bounds, err = partitionFor("dds.sales", partitionBy, "North America's")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(name, "dds.sales_p_north_america_s_"), name)
+	assert.Equal(t, "for values in ('North America''s')", bounds)
+}
+
+func TestPartitionTableNameTruncated(t *testing.T) {
+	name := partitionTableName("dds."+strings.Repeat("t", 70), "p20240101")
+	assert.Equal(t, "dds."+strings.Repeat("t", 53)+"_p20240101", name)
+}
+
+func TestPartitionKeysSQL(t *testing.T) {
+	assert.Equal(t,
+		"select distinct to_char(date_trunc('month', flight_date), 'YYYY-MM-DD') from stage where flight_date is not null",
+		partitionKeysSQL("stage", &configs.PartitionProfile{Type: configs.PARTITION_RANGE, Column: "flight_date", Granularity: "month"}))
+	assert.Equal(t,
+		"select distinct region::text from stage where region is not null",
+		partitionKeysSQL("stage", &configs.PartitionProfile{Type: configs.PARTITION_LIST, Column: "region"}))
+}
@@ -6,13 +6,13 @@ import (
 	"errors"
 )
 
-func (d *PostgresDBEngine) SimpleTest(sqlQuery string) (string, error) {
-	var count sql.NullString
+func (d *PostgresDBEngine) SimpleTest(sqlQuery string) (int64, error) {
+	var count sql.NullInt64
 	err := d.db.QueryRow(context.Background(), sqlQuery).Scan(&count)
 
 	if errors.Is(err, sql.ErrNoRows) {
-		return "", nil
+		return 0, nil
 	}
 
-	return count.String, err
+	return count.Int64, err
 }
@@ -7,6 +7,7 @@ import (
 	"hash/fnv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
@@ -158,7 +159,9 @@ func (d *PostgresDBEngine) Begin() (interface{}, error) {
 // CheckTableExists implements DBEngine.
 func (d *PostgresDBEngine) CheckTableExists(tx interface{}, tableName string) bool {
 	splitted := strings.Split(tableName, ".")
-	query := "SELECT count(DISTINCT table_name) from information_schema.tables WHERE table_schema=$1 and table_name=$2;"
+	// information_schema.tables does not list materialized views
+	query := `SELECT (SELECT count(DISTINCT table_name) from information_schema.tables WHERE table_schema=$1 and table_name=$2)
+		+ (SELECT count(*) from pg_matviews WHERE schemaname=$1 and matviewname=$2);`
 	var count int
 	err := tx.(pgx.Tx).QueryRow(context.Background(), query, splitted[0], splitted[1]).Scan(&count)
 	if err != nil {
@@ -167,6 +170,243 @@ func (d *PostgresDBEngine) CheckTableExists(tx interface{}, tableName string) bo
 	return count > 0
 }
 
+// GetRelationComment implements DBEngine.
+func (d *PostgresDBEngine) GetRelationComment(tx interface{}, relationName string) (string, error) {
+	query := "SELECT coalesce(obj_description(to_regclass($1), 'pg_class'), '');"
+	var comment string
+	err := tx.(pgx.Tx).QueryRow(context.Background(), query, relationName).Scan(&comment)
+	return comment, err
+}
+
+// GetMaxTimestamp implements DBEngine.
+func (d *PostgresDBEngine) GetMaxTimestamp(tx interface{}, tableName string, columnName string) (*time.Time, error) {
+	var maxTimestamp *time.Time
+	err := tx.(pgx.Tx).QueryRow(context.Background(), fmt.Sprintf("SELECT max(%s)::timestamptz FROM %s;", columnName, tableName)).Scan(&maxTimestamp)
+	return maxTimestamp, err
+}
+
+// CountRows implements DBEngine.
+func (d *PostgresDBEngine) CountRows(tx interface{}, sqlQuery string) (int64, error) {
+	var count int64
+	err := tx.(pgx.Tx).QueryRow(context.Background(), fmt.Sprintf("SELECT count(*) FROM (%s) AS teal_count_src;", sqlQuery)).Scan(&count)
+	return count, err
+}
+
+// ExportQuery implements DBEngine. The rows are fetched and written by teal,
+// since the database server can not be expected to see the files. Parquet is not supported.
+func (d *PostgresDBEngine) ExportQuery(tx interface{}, sqlQuery string, path string, format string, partitionBy []string) ([]string, error) {
+	writePath, err := prepareExportPath(path, partitionBy)
+	if err != nil {
+		return nil, err
+	}
+	paths, err := d.writeExport(tx, sqlQuery, writePath, format, partitionBy)
+	if err != nil {
+		abortExportPath(writePath, partitionBy)
+		return nil, err
+	}
+	if len(partitionBy) == 0 {
+		return paths, nil
+	}
+	return finishExportPath(path, writePath, partitionBy)
+}
+
+func (d *PostgresDBEngine) writeExport(tx interface{}, sqlQuery string, path string, format string, partitionBy []string) ([]string, error) {
+	rows, err := tx.(pgx.Tx).Query(context.Background(), sqlQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns := make([]string, len(rows.FieldDescriptions()))
+	for i, field := range rows.FieldDescriptions() {
+		columns[i] = field.Name
+	}
+	writer, err := newExportWriter(path, format, columns, partitionBy)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		values, err := rows.Values()
+		if err == nil {
+			err = writer.Write(values)
+		}
+		if err != nil {
+			writer.Close()
+			return nil, err
+		}
+	}
+	if err = rows.Err(); err != nil {
+		writer.Close()
+		return nil, err
+	}
+	return writer.Close()
+}
+
+// GetTableColumns implements DBEngine.
+func (d *PostgresDBEngine) GetTableColumns(tx interface{}, tableName string) ([]ColumnInfo, error) {
+	query := `SELECT a.attname, format_type(a.atttypid, a.atttypmod), NOT a.attnotnull
+		FROM pg_attribute a
+		WHERE a.attrelid = to_regclass($1) AND a.attnum > 0 AND NOT a.attisdropped
+		ORDER BY a.attnum;`
+	rows, err := tx.(pgx.Tx).Query(context.Background(), query, tableName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var columns []ColumnInfo
+	for rows.Next() {
+		var column ColumnInfo
+		if err := rows.Scan(&column.Name, &column.Type, &column.Nullable); err != nil {
+			return nil, err
+		}
+		columns = append(columns, column)
+	}
+	return columns, rows.Err()
+}
+
+// GetQueryColumns implements DBEngine. The query result is materialized into an
+// empty temporary table, so the types are formatted by format_type exactly like
+// the columns of a regular table.
+func (d *PostgresDBEngine) GetQueryColumns(tx interface{}, sqlQuery string) ([]ColumnInfo, error) {
+	const probeName = "teal_columns_probe"
+	probeSQL := fmt.Sprintf("DROP TABLE IF EXISTS pg_temp.%s; CREATE TEMPORARY TABLE %s ON COMMIT DROP AS SELECT * FROM (%s) AS teal_probe_src LIMIT 0;", probeName, probeName, sqlQuery)
+	if _, err := tx.(pgx.Tx).Exec(context.Background(), probeSQL); err != nil {
+		return nil, err
+	}
+	columns, err := d.GetTableColumns(tx, "pg_temp."+probeName)
+	if err != nil {
+		return nil, err
+	}
+	_, err = tx.(pgx.Tx).Exec(context.Background(), fmt.Sprintf("DROP TABLE pg_temp.%s;", probeName))
+	return columns, err
+}
+
+// LoadSeed implements DBEngine. The file is read by teal, since the database
+// server can not be expected to see it. Parquet seeds are not supported, teal gen rejects them.
+func (d *PostgresDBEngine) LoadSeed(tx interface{}, tableName string, filePath string, format string, columnTypes map[string]string) error {
+	data, err := readSeedFile(filePath, format)
+	if err != nil {
+		return err
+	}
+
+	columns := make([]string, len(data.Columns))
+	columnDefinitions := make([]string, len(data.Columns))
+	for i, columnName := range data.Columns {
+		columns[i] = pgx.Identifier{columnName}.Sanitize()
+		columnType, ok := columnTypes[columnName]
+		if !ok {
+			columnType = inferSeedColumnType(data.Rows, i)
+		}
+		columnDefinitions[i] = columns[i] + " " + columnType
+	}
+	err = d.Exec(tx, fmt.Sprintf("drop table if exists %s; create table %s (%s);", tableName, tableName, strings.Join(columnDefinitions, ", ")))
+	if err != nil {
+		return err
+	}
+
+	const batchSize = 1000
+	for start := 0; start < len(data.Rows); start += batchSize {
+		end := min(start+batchSize, len(data.Rows))
+		values := make([]string, 0, end-start)
+		for _, row := range data.Rows[start:end] {
+			literals := make([]string, len(columns))
+			for i := range columns {
+				if i < len(row) {
+					literals[i] = sqlLiteral(row[i])
+				} else {
+					literals[i] = sqlLiteral(nil)
+				}
+			}
+			values = append(values, "("+strings.Join(literals, ", ")+")")
+		}
+		_, err = tx.(pgx.Tx).Exec(context.Background(), fmt.Sprintf("insert into %s (%s) values %s;", tableName, strings.Join(columns, ", "), strings.Join(values, ", ")))
+		if err != nil {
+			log.Error().Caller().Str("table", tableName).Err(err).Msg("Failed to insert seed rows")
+			return err
+		}
+	}
+	return nil
+}
+
+// CreatePartitionedTable implements DBEngine. The columns are taken from the query,
+// rows with a NULL partition key go to the default partition.
+func (d *PostgresDBEngine) CreatePartitionedTable(tx interface{}, tableName string, sqlQuery string, partitionBy *configs.PartitionProfile) error {
+	columns, err := d.GetQueryColumns(tx, sqlQuery)
+	if err != nil {
+		return err
+	}
+	columnDefinitions := make([]string, len(columns))
+	for i, column := range columns {
+		columnDefinitions[i] = pgx.Identifier{column.Name}.Sanitize() + " " + column.Type
+	}
+	return d.Exec(tx, fmt.Sprintf("create table %s (%s) partition by %s (%s); create table %s partition of %s default;",
+		tableName, strings.Join(columnDefinitions, ", "), partitionBy.Type, partitionBy.Column,
+		partitionTableName(tableName, "default"), tableName))
+}
+
+// InsertPartitions implements DBEngine. The query result is staged in a temporary table
+// to find the partition keys, missing partitions are created before the insert.
+func (d *PostgresDBEngine) InsertPartitions(tx interface{}, tableName string, sqlQuery string, partitionBy *configs.PartitionProfile, replace bool) ([]string, error) {
+	const stageName = "teal_partition_stage"
+	stageSQL := fmt.Sprintf("DROP TABLE IF EXISTS pg_temp.%s; CREATE TEMPORARY TABLE %s ON COMMIT DROP AS SELECT * FROM (%s) AS teal_stage_src;", stageName, stageName, sqlQuery)
+	if err := d.Exec(tx, stageSQL); err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.(pgx.Tx).Query(context.Background(), partitionKeysSQL("pg_temp."+stageName, partitionBy))
+	if err != nil {
+		return nil, err
+	}
+	keys, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, err
+	}
+
+	partitions := make([]string, 0, len(keys))
+	for _, key := range keys {
+		partitionName, bounds, err := partitionFor(tableName, partitionBy, key)
+		if err != nil {
+			return nil, err
+		}
+		statement := fmt.Sprintf("create table if not exists %s partition of %s %s;", partitionName, tableName, bounds)
+		if replace {
+			statement += fmt.Sprintf(" truncate table %s;", partitionName)
+		}
+		if err := d.Exec(tx, statement); err != nil {
+			return nil, err
+		}
+		partitions = append(partitions, partitionName)
+	}
+
+	stageColumns, err := d.GetTableColumns(tx, "pg_temp."+stageName)
+	if err != nil {
+		return nil, err
+	}
+	columns := make([]string, len(stageColumns))
+	for i, column := range stageColumns {
+		columns[i] = pgx.Identifier{column.Name}.Sanitize()
+	}
+	fields := strings.Join(columns, ", ")
+	err = d.Exec(tx, fmt.Sprintf("insert into %s (%s) select %s from pg_temp.%s; drop table pg_temp.%s;", tableName, fields, fields, stageName, stageName))
+	return partitions, err
+}
+
+// IsRetryableError implements DBEngine. Serialization failures, deadlocks and lock timeouts are retryable.
+func (d *PostgresDBEngine) IsRetryableError(err error) bool {
+	return isRetryablePostgresError(err)
+}
+
+// ApplyGrants implements DBEngine.
+func (d *PostgresDBEngine) ApplyGrants(tx interface{}, relationName string, grants map[string][]string) error {
+	for _, statement := range grantStatements(relationName, grants) {
+		if err := d.Exec(tx, statement); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Close implements DBEngine.
 func (d *PostgresDBEngine) Close() error {
 	log.Debug().Str("host", d.dbConnection.Config.Host).Int("port", d.dbConnection.Config.Port).Msg("disconnected")
@@ -0,0 +1,48 @@
+package drivers
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// retryablePostgresCodes are the SQLSTATE codes of transient failures:
+// serialization_failure, deadlock_detected and lock_not_available
+var retryablePostgresCodes = map[string]bool{
+	"40001": true,
+	"40P01": true,
+	"55P03": true,
+}
+
+// retryableDuckDBMessages are the fragments of the DuckDB errors raised by concurrent writers.
+// The messages are matched in lower case. Conflicts are retryable only as transaction errors,
+// binder and constraint errors of ON CONFLICT clauses are permanent.
+var retryableDuckDBMessages = []string{
+	"write-write conflict",
+	"could not set lock on file",
+}
+
+// DUCKDB_TRANSACTION_ERROR prefixes the DuckDB errors of aborted transactions, e.g. "Conflict on tuple deletion!"
+const DUCKDB_TRANSACTION_ERROR = "transactioncontext error"
+
+func isRetryablePostgresError(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && retryablePostgresCodes[pgErr.Code]
+}
+
+func isRetryableDuckDBError(err error) bool {
+	if err == nil {
+		return false
+	}
+	message := strings.ToLower(err.Error())
+	if strings.Contains(message, DUCKDB_TRANSACTION_ERROR) && strings.Contains(message, "conflict") {
+		return true
+	}
+	for _, fragment := range retryableDuckDBMessages {
+		if strings.Contains(message, fragment) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,29 @@
+package drivers
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsRetryablePostgresError(t *testing.T) {
+	assert.True(t, isRetryablePostgresError(&pgconn.PgError{Code: "40001"}))
+	assert.True(t, isRetryablePostgresError(fmt.Errorf("insert: %w", &pgconn.PgError{Code: "40P01"})))
+	assert.True(t, isRetryablePostgresError(&pgconn.PgError{Code: "55P03"}))
+	assert.False(t, isRetryablePostgresError(&pgconn.PgError{Code: "42P01"}))
+	assert.False(t, isRetryablePostgresError(errors.New("serialization failure")))
+	assert.False(t, isRetryablePostgresError(nil))
+}
+
+func TestIsRetryableDuckDBError(t *testing.T) {
+	assert.True(t, isRetryableDuckDBError(errors.New(`TransactionContext Error: Catalog write-write conflict on alter with "orders"`)))
+	assert.True(t, isRetryableDuckDBError(errors.New("TransactionContext Error: Conflict on tuple deletion!")))
+	assert.True(t, isRetryableDuckDBError(errors.New(`IO Error: Could not set lock on file "dwh.duckdb"`)))
+	assert.False(t, isRetryableDuckDBError(errors.New("Catalog Error: Table with name orders does not exist!")))
+	assert.False(t, isRetryableDuckDBError(errors.New(`Binder Error: The specified columns as conflict target are not referenced by a UNIQUE/PRIMARY KEY CONSTRAINT or INDEX`)))
+	assert.False(t, isRetryableDuckDBError(errors.New(`Constraint Error: Duplicate key "id: 1" violates primary key constraint. ON CONFLICT DO UPDATE can not update the same row twice`)))
+	assert.False(t, isRetryableDuckDBError(nil))
+}
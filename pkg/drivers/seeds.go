@@ -0,0 +1,188 @@
+package drivers
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Supported formats of seed files
+const (
+	SEED_FORMAT_CSV     = "csv"
+	SEED_FORMAT_PARQUET = "parquet"
+	SEED_FORMAT_JSON    = "json"
+)
+
+// seedData is a seed file read into memory. A nil value is NULL.
+type seedData struct {
+	Columns []string
+	Rows    [][]*string
+}
+
+// readSeedFile reads CSV (with a header) and JSON (an array of objects or
+// newline delimited objects) seeds. Empty CSV fields and JSON nulls become NULL.
+func readSeedFile(filePath string, format string) (*seedData, error) {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	switch format {
+	case SEED_FORMAT_CSV:
+		return readCSVSeed(content)
+	case SEED_FORMAT_JSON:
+		return readJSONSeed(content)
+	default:
+		return nil, fmt.Errorf("seed format %s is not supported by this driver", format)
+	}
+}
+
+func readCSVSeed(content []byte) (*seedData, error) {
+	records, err := csv.NewReader(bytes.NewReader(content)).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("seed file has no header")
+	}
+	data := &seedData{Columns: records[0]}
+	for _, record := range records[1:] {
+		row := make([]*string, len(record))
+		for i := range record {
+			if record[i] != "" {
+				row[i] = &record[i]
+			}
+		}
+		data.Rows = append(data.Rows, row)
+	}
+	return data, nil
+}
+
+func readJSONSeed(content []byte) (*seedData, error) {
+	decoder := json.NewDecoder(bytes.NewReader(bytes.TrimSpace(content)))
+	isArray := len(bytes.TrimSpace(content)) > 0 && bytes.TrimSpace(content)[0] == '['
+	if isArray {
+		if _, err := decoder.Token(); err != nil {
+			return nil, err
+		}
+	}
+
+	data := &seedData{}
+	columnIndex := make(map[string]int)
+	var objects []map[string]*string
+	for decoder.More() {
+		object, keys, err := decodeSeedObject(decoder)
+		if err != nil {
+			return nil, err
+		}
+		for _, key := range keys {
+			if _, ok := columnIndex[key]; !ok {
+				columnIndex[key] = len(data.Columns)
+				data.Columns = append(data.Columns, key)
+			}
+		}
+		objects = append(objects, object)
+	}
+	if isArray {
+		if _, err := decoder.Token(); err != nil && err != io.EOF {
+			return nil, err
+		}
+	}
+
+	for _, object := range objects {
+		row := make([]*string, len(data.Columns))
+		for key, value := range object {
+			row[columnIndex[key]] = value
+		}
+		data.Rows = append(data.Rows, row)
+	}
+	return data, nil
+}
+
+// decodeSeedObject decodes one JSON object keeping the order of its keys.
+// Nested objects and arrays are kept as JSON text.
+func decodeSeedObject(decoder *json.Decoder) (map[string]*string, []string, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, nil, err
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != '{' {
+		return nil, nil, fmt.Errorf("seed rows must be JSON objects, got %v", token)
+	}
+	object := make(map[string]*string)
+	var keys []string
+	for decoder.More() {
+		keyToken, err := decoder.Token()
+		if err != nil {
+			return nil, nil, err
+		}
+		key := keyToken.(string)
+		var raw json.RawMessage
+		if err := decoder.Decode(&raw); err != nil {
+			return nil, nil, err
+		}
+		keys = append(keys, key)
+		switch {
+		case string(raw) == "null":
+			object[key] = nil
+		case raw[0] == '"':
+			var value string
+			if err := json.Unmarshal(raw, &value); err != nil {
+				return nil, nil, err
+			}
+			object[key] = &value
+		default:
+			value := string(raw)
+			object[key] = &value
+		}
+	}
+	if _, err := decoder.Token(); err != nil {
+		return nil, nil, err
+	}
+	return object, keys, nil
+}
+
+// inferSeedColumnType picks the narrowest PostgreSQL type all values of the column fit into
+func inferSeedColumnType(rows [][]*string, column int) string {
+	isInt, isFloat, isBool, hasValues := true, true, true, false
+	for _, row := range rows {
+		if column >= len(row) || row[column] == nil {
+			continue
+		}
+		hasValues = true
+		value := strings.TrimSpace(*row[column])
+		if _, err := strconv.ParseInt(value, 10, 64); err != nil {
+			isInt = false
+		}
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			isFloat = false
+		}
+		if lower := strings.ToLower(value); lower != "true" && lower != "false" {
+			isBool = false
+		}
+	}
+	switch {
+	case !hasValues:
+		return "text"
+	case isInt:
+		return "bigint"
+	case isFloat:
+		return "double precision"
+	case isBool:
+		return "boolean"
+	default:
+		return "text"
+	}
+}
+
+// sqlLiteral quotes a value as an untyped SQL literal, PostgreSQL coerces it to the column type
+func sqlLiteral(value *string) string {
+	if value == nil {
+		return "NULL"
+	}
+	return "'" + strings.ReplaceAll(*value, "'", "''") + "'"
+}
@@ -0,0 +1,66 @@
+package drivers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func values(row []*string) []any {
+	result := make([]any, len(row))
+	for i, v := range row {
+		if v != nil {
+			result[i] = *v
+		}
+	}
+	return result
+}
+
+func TestReadCSVSeed(t *testing.T) {
+	data, err := readCSVSeed([]byte("code,name,rank\nAMS,\"Schiphol, NL\",1\nJFK,,2\n"))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"code", "name", "rank"}, data.Columns)
+	require.Len(t, data.Rows, 2)
+	assert.Equal(t, []any{"AMS", "Schiphol, NL", "1"}, values(data.Rows[0]))
+	assert.Equal(t, []any{"JFK", nil, "2"}, values(data.Rows[1]))
+}
+
+func TestReadJSONSeed(t *testing.T) {
+	expectedColumns := []string{"code", "rank", "tags", "active"}
+	for name, content := range map[string]string{
+		"array":  `[{"code": "AMS", "rank": 1, "tags": ["hub"]}, {"code": null, "rank": 2.5, "active": true}]`,
+		"ndjson": "{\"code\": \"AMS\", \"rank\": 1, \"tags\": [\"hub\"]}\n{\"code\": null, \"rank\": 2.5, \"active\": true}\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			data, err := readJSONSeed([]byte(content))
+			require.NoError(t, err)
+
+			assert.Equal(t, expectedColumns, data.Columns)
+			require.Len(t, data.Rows, 2)
+			assert.Equal(t, []any{"AMS", "1", `["hub"]`, nil}, values(data.Rows[0]))
+			assert.Equal(t, []any{nil, "2.5", nil, "true"}, values(data.Rows[1]))
+		})
+	}
+}
+
+func TestInferSeedColumnType(t *testing.T) {
+	str := func(s string) *string { return &s }
+	rows := [][]*string{
+		{str("1"), str("1.5"), str("true"), str("AMS"), nil},
+		{str("20"), str("2"), str("FALSE"), str("7"), nil},
+		{nil, nil, nil, nil, nil},
+	}
+	assert.Equal(t, "bigint", inferSeedColumnType(rows, 0))
+	assert.Equal(t, "double precision", inferSeedColumnType(rows, 1))
+	assert.Equal(t, "boolean", inferSeedColumnType(rows, 2))
+	assert.Equal(t, "text", inferSeedColumnType(rows, 3))
+	assert.Equal(t, "text", inferSeedColumnType(rows, 4))
+}
+
+func TestSQLLiteral(t *testing.T) {
+	value := "O'Hare"
+	assert.Equal(t, "'O''Hare'", sqlLiteral(&value))
+	assert.Equal(t, "NULL", sqlLiteral(nil))
+}
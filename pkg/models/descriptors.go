@@ -14,6 +14,29 @@ type SQLModelDescriptor struct {
 	Upstreams        []string
 	Downstreams      []string
 	ModelProfile     *configs.ModelProfile
+
+	// Materialized view statements, generated only for the materialized_view materialization
+	CreateMaterializedViewSQL  string
+	RefreshMaterializedViewSQL string
+	DropMaterializedViewSQL    string
+
+	// Build-then-swap statements, generated only for the table materialization.
+	// The new data is built in a shadow table, which replaces the existing one.
+	CreateShadowTableSQL string
+	SwapTableSQL         string
+	ReplaceFromShadowSQL string
+	DropShadowTableSQL   string
+
+	// ReplaceViewSQL is the CREATE OR REPLACE VIEW statement used when the view has drifted
+	ReplaceViewSQL string
+
+	// CreateIndexesSQL creates the primary key and the indexes of a partitioned table,
+	// the table itself is created by the driver from the columns of the query
+	CreateIndexesSQL string
+
+	// ContractSQL is the model query before the casts of enforce_types, generated only with enforce_types.
+	// The columns of the contract are checked against it.
+	ContractSQL string
 }
 
 type SQLModelTestDescriptor struct {
@@ -23,9 +46,48 @@ type SQLModelTestDescriptor struct {
 	TestProfile  *configs.TestProfile
 }
 
+type SeedDescriptor struct {
+	Name string
+	// FilePath is relative to the project directory
+	FilePath     string
+	Format       string
+	Upstreams    []string
+	Downstreams  []string
+	ModelProfile *configs.ModelProfile
+}
+
 type RawModelDescriptor struct {
 	Name         string
 	Upstreams    []string
 	Downstreams  []string
 	ModelProfile *configs.ModelProfile
 }
+
+// SourceDescriptor describes a table of profile.yaml sources, its execution is the freshness check
+type SourceDescriptor struct {
+	Name          string
+	Downstreams   []string
+	SourceProfile *configs.SourceTableProfile
+}
+
+// UnitTestDescriptor describes a unit test of a SQL model: the model runs against fixture tables
+// replacing its upstreams and must return the expected rows
+type UnitTestDescriptor struct {
+	Name        string
+	Description string
+	ModelName   string
+	Fixtures    []*UnitTestFixture
+	// ExpectedSQL selects the expected rows, only ExpectedColumns of the model are compared
+	ExpectedSQL     string
+	ExpectedColumns []string
+	// VarsJSON overrides the vars of profile.yaml, RunDate is the logical date of the test, both are optional
+	VarsJSON string
+	RunDate  string
+}
+
+// UnitTestFixture is a table created in place of an upstream, Relation is the name the model refers to
+type UnitTestFixture struct {
+	Name     string
+	Relation string
+	SQL      string
+}
@@ -0,0 +1,56 @@
+package processing
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/go-teal/teal/pkg/drivers"
+)
+
+// ErrAtomicRollback marks the errors of atomic executions, all their changes are rolled back
+var ErrAtomicRollback = errors.New("atomic execution rolled back")
+
+// errRollbackFailed marks the atomic executions whose rollback failed, their changes may be left in the database
+var errRollbackFailed = errors.New("rollback failed")
+
+// atomicConnection shares one transaction between all the steps of an atomic execution.
+// Begin returns the shared transaction, Commit and Rollback are left to the owner of the transaction.
+type atomicConnection struct {
+	drivers.DBDriver
+	tx interface{}
+}
+
+// Begin implements drivers.DBDriver.
+func (c *atomicConnection) Begin() (interface{}, error) {
+	return c.tx, nil
+}
+
+// Commit implements drivers.DBDriver.
+func (c *atomicConnection) Commit(tx interface{}) error {
+	return nil
+}
+
+// Rollback implements drivers.DBDriver.
+func (c *atomicConnection) Rollback(tx interface{}) error {
+	return nil
+}
+
+// runAtomic calls execute with a connection sharing one transaction,
+// which is committed on success and rolled back entirely on error
+func runAtomic(dbConnection drivers.DBDriver, execute func(dbConnection drivers.DBDriver) error) error {
+	tx, err := dbConnection.Begin()
+	if err != nil {
+		return err
+	}
+	err = execute(&atomicConnection{DBDriver: dbConnection, tx: tx})
+	if err != nil {
+		if rollbackErr := dbConnection.Rollback(tx); rollbackErr != nil {
+			return fmt.Errorf("%w: %w (%w: %v)", ErrAtomicRollback, err, errRollbackFailed, rollbackErr)
+		}
+		return fmt.Errorf("%w: %w", ErrAtomicRollback, err)
+	}
+	if err = dbConnection.Commit(tx); err != nil {
+		return fmt.Errorf("%w: %w", ErrAtomicRollback, err)
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package processing
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-teal/teal/pkg/drivers"
+	"github.com/stretchr/testify/assert"
+)
+
+type txRecorder struct {
+	drivers.DBDriver
+	begins    int
+	commits   int
+	rollbacks int
+}
+
+func (r *txRecorder) Begin() (interface{}, error) {
+	r.begins++
+	return r.begins, nil
+}
+
+func (r *txRecorder) Commit(tx interface{}) error {
+	r.commits++
+	return nil
+}
+
+func (r *txRecorder) Rollback(tx interface{}) error {
+	r.rollbacks++
+	return nil
+}
+
+type failingRollback struct {
+	txRecorder
+}
+
+func (r *failingRollback) Rollback(tx interface{}) error {
+	return errors.New("connection lost")
+}
+
+func TestRunAtomicSharesOneTransaction(t *testing.T) {
+	recorder := &txRecorder{}
+	err := runAtomic(recorder, func(dbConnection drivers.DBDriver) error {
+		for i := 0; i < 3; i++ {
+			tx, _ := dbConnection.Begin()
+			assert.Equal(t, 1, tx)
+			dbConnection.Commit(tx)
+		}
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, recorder.begins)
+	assert.Equal(t, 1, recorder.commits)
+	assert.Equal(t, 0, recorder.rollbacks)
+}
+
+func TestRunAtomicRollsBackOnError(t *testing.T) {
+	recorder := &txRecorder{}
+	failure := errors.New("insert failed")
+	err := runAtomic(recorder, func(dbConnection drivers.DBDriver) error {
+		tx, _ := dbConnection.Begin()
+		dbConnection.Commit(tx)
+		return failure
+	})
+
+	assert.ErrorIs(t, err, ErrAtomicRollback)
+	assert.ErrorIs(t, err, failure)
+	assert.Equal(t, 0, recorder.commits)
+	assert.Equal(t, 1, recorder.rollbacks)
+}
+
+func TestRunAtomicReportsFailedRollback(t *testing.T) {
+	failure := errors.New("insert failed")
+	err := runAtomic(&failingRollback{}, func(dbConnection drivers.DBDriver) error {
+		return failure
+	})
+
+	assert.ErrorIs(t, err, ErrAtomicRollback)
+	assert.ErrorIs(t, err, errRollbackFailed)
+	assert.ErrorIs(t, err, failure)
+}
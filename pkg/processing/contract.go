@@ -0,0 +1,215 @@
+package processing
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-teal/teal/pkg/configs"
+	"github.com/go-teal/teal/pkg/drivers"
+	"github.com/rs/zerolog/log"
+)
+
+// columnTypeAliases maps the spellings of the same type in DuckDB, PostgreSQL and profiles to one name
+var columnTypeAliases = map[string]string{
+	"int":                         "integer",
+	"int4":                        "integer",
+	"signed":                      "integer",
+	"int8":                        "bigint",
+	"long":                        "bigint",
+	"int2":                        "smallint",
+	"short":                       "smallint",
+	"text":                        "varchar",
+	"string":                      "varchar",
+	"character varying":           "varchar",
+	"float8":                      "double",
+	"double precision":            "double",
+	"float4":                      "real",
+	"bool":                        "boolean",
+	"logical":                     "boolean",
+	"numeric":                     "decimal",
+	"datetime":                    "timestamp",
+	"timestamp without time zone": "timestamp",
+	"timestamp with time zone":    "timestamptz",
+	"time without time zone":      "time",
+	"bytea":                       "blob",
+}
+
+// normalizeColumnType returns the base name and the modifiers of a type, e.g. "decimal" and "(18,2)"
+func normalizeColumnType(columnType string) (string, string) {
+	columnType = strings.Join(strings.Fields(strings.ToLower(columnType)), " ")
+	base, modifiers := columnType, ""
+	if i := strings.Index(columnType, "("); i >= 0 {
+		base, modifiers = strings.TrimSpace(columnType[:i]), strings.ReplaceAll(columnType[i:], " ", "")
+	}
+	if alias, ok := columnTypeAliases[base]; ok {
+		base = alias
+	}
+	return base, modifiers
+}
+
+// columnTypeMatches compares a type of the contract with a type reported by the database,
+// a contract type without modifiers matches any modifiers, e.g. decimal matches DECIMAL(18,3)
+func columnTypeMatches(contractType string, actualType string) bool {
+	contractBase, contractModifiers := normalizeColumnType(contractType)
+	actualBase, actualModifiers := normalizeColumnType(actualType)
+	return contractBase == actualBase && (contractModifiers == "" || contractModifiers == actualModifiers)
+}
+
+// contractViolations compares the columns of the query with the contract, names are compared case-insensitively
+func contractViolations(columns []*configs.ColumnProfile, queryColumns []drivers.ColumnInfo, checkTypes bool) []string {
+	var violations []string
+	queryColumnsMap := make(map[string]drivers.ColumnInfo, len(queryColumns))
+	for _, c := range queryColumns {
+		queryColumnsMap[strings.ToLower(c.Name)] = c
+	}
+	declared := make(map[string]bool, len(columns))
+	for _, column := range columns {
+		declared[strings.ToLower(column.Name)] = true
+		queryColumn, ok := queryColumnsMap[strings.ToLower(column.Name)]
+		if !ok {
+			violations = append(violations, fmt.Sprintf("missing column %s", column.Name))
+			continue
+		}
+		if checkTypes && column.Type != "" && !columnTypeMatches(column.Type, queryColumn.Type) {
+			violations = append(violations, fmt.Sprintf("column %s is %s, the contract requires %s", column.Name, queryColumn.Type, column.Type))
+		}
+	}
+	for _, c := range queryColumns {
+		if !declared[strings.ToLower(c.Name)] {
+			violations = append(violations, fmt.Sprintf("column %s is not in the contract", c.Name))
+		}
+	}
+	return violations
+}
+
+// checkContract compares the columns of the model query with the contract before the materialization.
+// With enforce_types the query before the casts is checked, the types are cast anyway.
+func (s *SQLModelAsset) checkContract(ctx *TaskContext, isIncremental bool) error {
+	if len(s.descriptor.ModelProfile.Columns) == 0 {
+		return nil
+	}
+	dbConnection := s.getDBConnection()
+	sqlTemplate := s.descriptor.RawSQL
+	if s.descriptor.ContractSQL != "" {
+		sqlTemplate = s.descriptor.ContractSQL
+	}
+
+	tx, err := dbConnection.Begin()
+	if err != nil {
+		log.Error().Caller().
+			Str("taskId", ctx.TaskID).
+			Str("taskUUID", ctx.TaskUUID).
+			Str("assetName", s.descriptor.Name).
+			Err(err).
+			Msg("Failed to begin transaction")
+		defer dbConnection.Rollback(tx)
+		return err
+	}
+
+	s.functions["IsIncremental"] = func() bool {
+		return isIncremental
+	}
+	context := MergePongo2Context(
+		FromConnectionContext(dbConnection, tx, s.descriptor.Name, s.functions),
+		FromTaskContextPongo2(ctx),
+	)
+	sqlQuery, err := renderSQL(sqlTemplate, context)
+	if err != nil {
+		defer dbConnection.Rollback(tx)
+		log.Error().Caller().Stack().
+			Str("taskId", ctx.TaskID).
+			Str("taskUUID", ctx.TaskUUID).
+			Str("assetName", s.descriptor.Name).
+			Str("sql", sqlTemplate).
+			Err(err).
+			Msg("Failed to render template")
+		return err
+	}
+
+	queryColumns, err := dbConnection.GetQueryColumns(tx, sqlQuery)
+	if err != nil {
+		defer dbConnection.Rollback(tx)
+		log.Error().Caller().
+			Str("taskId", ctx.TaskID).
+			Str("taskUUID", ctx.TaskUUID).
+			Str("assetName", s.descriptor.Name).
+			Err(err).
+			Msg("Failed to get the columns of the query")
+		return err
+	}
+	violations := contractViolations(s.descriptor.ModelProfile.Columns, queryColumns, !s.descriptor.ModelProfile.EnforceTypes)
+	if len(violations) > 0 {
+		defer dbConnection.Rollback(tx)
+		err = fmt.Errorf("contract of %s is violated: %s", s.descriptor.Name, strings.Join(violations, "; "))
+		log.Error().
+			Str("taskId", ctx.TaskID).
+			Str("taskUUID", ctx.TaskUUID).
+			Str("assetName", s.descriptor.Name).
+			Strs("violations", violations).
+			Err(err).
+			Msg("Contract violated")
+		return err
+	}
+	return dbConnection.Commit(tx)
+}
+
+// checkNotNullColumns counts the nulls of the not nullable columns of the contract after the materialization
+func (s *SQLModelAsset) checkNotNullColumns(ctx *TaskContext) error {
+	var notNullColumns []string
+	for _, column := range s.descriptor.ModelProfile.Columns {
+		if !column.Nullable {
+			notNullColumns = append(notNullColumns, column.Name)
+		}
+	}
+	if len(notNullColumns) == 0 {
+		return nil
+	}
+	dbConnection := s.getDBConnection()
+
+	tx, err := dbConnection.Begin()
+	if err != nil {
+		log.Error().Caller().
+			Str("taskId", ctx.TaskID).
+			Str("taskUUID", ctx.TaskUUID).
+			Str("assetName", s.descriptor.Name).
+			Err(err).
+			Msg("Failed to begin transaction")
+		defer dbConnection.Rollback(tx)
+		return err
+	}
+
+	var violations []string
+	for _, column := range notNullColumns {
+		sqlQuery := fmt.Sprintf("select 1 from %s where %s is null", s.descriptor.Name, column)
+		count, err := dbConnection.CountRows(tx, sqlQuery)
+		if err != nil {
+			defer dbConnection.Rollback(tx)
+			log.Error().Caller().
+				Str("taskId", ctx.TaskID).
+				Str("taskUUID", ctx.TaskUUID).
+				Str("assetName", s.descriptor.Name).
+				Str("sql", sqlQuery).
+				Err(err).
+				Msg("Failed to count nulls")
+			return err
+		}
+		if count > 0 {
+			violations = append(violations, fmt.Sprintf("column %s has %d null values", column, count))
+		}
+	}
+	if err = dbConnection.Commit(tx); err != nil {
+		return err
+	}
+	if len(violations) > 0 {
+		err = fmt.Errorf("contract of %s is violated: %s", s.descriptor.Name, strings.Join(violations, "; "))
+		log.Error().
+			Str("taskId", ctx.TaskID).
+			Str("taskUUID", ctx.TaskUUID).
+			Str("assetName", s.descriptor.Name).
+			Strs("violations", violations).
+			Err(err).
+			Msg("Contract violated")
+		return err
+	}
+	return nil
+}
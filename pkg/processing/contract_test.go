@@ -0,0 +1,61 @@
+package processing
+
+import (
+	"testing"
+
+	"github.com/go-teal/teal/pkg/configs"
+	"github.com/go-teal/teal/pkg/drivers"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v2"
+)
+
+func TestContractViolations(t *testing.T) {
+	var columns []*configs.ColumnProfile
+	assert.NoError(t, yaml.Unmarshal([]byte(`
+- name: id
+  type: int
+  nullable: false
+- name: amount
+  type: decimal
+- name: name
+  type: text
+- name: created_at
+  type: timestamptz
+`), &columns))
+	assert.False(t, columns[0].Nullable)
+	assert.True(t, columns[1].Nullable, "columns are nullable by default")
+
+	// PostgreSQL spelling
+	assert.Empty(t, contractViolations(columns, []drivers.ColumnInfo{
+		{Name: "id", Type: "integer"},
+		{Name: "amount", Type: "numeric(18,2)"},
+		{Name: "name", Type: "character varying(20)"},
+		{Name: "created_at", Type: "timestamp with time zone"},
+	}, true))
+	// DuckDB spelling
+	assert.Empty(t, contractViolations(columns, []drivers.ColumnInfo{
+		{Name: "ID", Type: "INTEGER"},
+		{Name: "amount", Type: "DECIMAL(18,2)"},
+		{Name: "name", Type: "VARCHAR"},
+		{Name: "created_at", Type: "TIMESTAMP WITH TIME ZONE"},
+	}, true))
+
+	queryColumns := []drivers.ColumnInfo{
+		{Name: "id", Type: "BIGINT"},
+		{Name: "amount", Type: "DOUBLE"},
+		{Name: "name", Type: "VARCHAR"},
+		{Name: "comment", Type: "VARCHAR"},
+	}
+	assert.Equal(t, []string{
+		"column id is BIGINT, the contract requires int",
+		"column amount is DOUBLE, the contract requires decimal",
+		"missing column created_at",
+		"column comment is not in the contract",
+	}, contractViolations(columns, queryColumns, true))
+	assert.Equal(t, []string{
+		"missing column created_at",
+		"column comment is not in the contract",
+	}, contractViolations(columns, queryColumns, false), "enforced types are not compared")
+
+	assert.False(t, columnTypeMatches("decimal(10,2)", "DECIMAL(18,2)"))
+}
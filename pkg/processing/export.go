@@ -0,0 +1,106 @@
+package processing
+
+import (
+	"time"
+
+	pongo2 "github.com/flosch/pongo2/v6"
+	"github.com/rs/zerolog/log"
+)
+
+// exportPathContext adds the functions only the export path can use
+func exportPathContext(now time.Time) pongo2.Context {
+	return pongo2.Context{
+		// Now formats the start of the export with a Go layout, e.g. {{ Now("2006-01-02") }}
+		"Now": func(layout string) string {
+			return now.Format(layout)
+		},
+	}
+}
+
+// export writes the result of the model query to files, returns the paths of the written files
+func (s *SQLModelAsset) export(ctx *TaskContext) (interface{}, error) {
+	exportProfile := s.descriptor.ModelProfile.Export
+	dbConnection := s.getDBConnection()
+
+	if s.descriptor.ModelProfile.PersistInputs {
+		err := s.persistInputs(ctx.Input)
+		if err != nil {
+			log.Error().Caller().
+				Str("taskId", ctx.TaskID).
+				Str("taskUUID", ctx.TaskUUID).
+				Str("assetName", s.descriptor.Name).
+				Err(err).
+				Msg("Failed to persist inputs")
+			return nil, err
+		}
+	}
+
+	s.functions["IsIncremental"] = func() bool {
+		return false
+	}
+
+	tx, err := dbConnection.Begin()
+	if err != nil {
+		log.Error().Caller().
+			Str("taskId", ctx.TaskID).
+			Str("taskUUID", ctx.TaskUUID).
+			Str("assetName", s.descriptor.Name).
+			Err(err).
+			Msg("Failed to begin transaction")
+		defer dbConnection.Rollback(tx)
+		return nil, err
+	}
+
+	context := MergePongo2Context(
+		FromConnectionContext(dbConnection, tx, s.descriptor.Name, s.functions),
+		FromTaskContextPongo2(ctx),
+	)
+	sqlQuery, err := renderSQL(s.descriptor.RawSQL, context)
+	if err != nil {
+		defer dbConnection.Rollback(tx)
+		log.Error().Caller().
+			Str("taskId", ctx.TaskID).
+			Str("taskUUID", ctx.TaskUUID).
+			Str("assetName", s.descriptor.Name).
+			Err(err).
+			Msg("Failed to render template")
+		return nil, err
+	}
+	path, err := renderSQL(exportProfile.Path, MergePongo2Context(context, exportPathContext(time.Now())))
+	if err != nil {
+		defer dbConnection.Rollback(tx)
+		log.Error().Caller().
+			Str("taskId", ctx.TaskID).
+			Str("taskUUID", ctx.TaskUUID).
+			Str("assetName", s.descriptor.Name).
+			Str("path", exportProfile.Path).
+			Err(err).
+			Msg("Failed to render export path")
+		return nil, err
+	}
+
+	paths, err := dbConnection.ExportQuery(tx, sqlQuery, path, exportProfile.Format, exportProfile.PartitionBy)
+	if err != nil {
+		defer dbConnection.Rollback(tx)
+		log.Error().Caller().
+			Str("taskId", ctx.TaskID).
+			Str("taskUUID", ctx.TaskUUID).
+			Str("assetName", s.descriptor.Name).
+			Str("path", path).
+			Err(err).
+			Msg("Failed to export")
+		return nil, err
+	}
+	if err = dbConnection.Commit(tx); err != nil {
+		return nil, err
+	}
+
+	log.Info().
+		Str("taskId", ctx.TaskID).
+		Str("taskUUID", ctx.TaskUUID).
+		Str("assetName", s.descriptor.Name).
+		Str("format", exportProfile.Format).
+		Strs("paths", paths).
+		Msg("Exported")
+	return paths, nil
+}
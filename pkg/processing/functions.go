@@ -33,6 +33,7 @@ func FromConnectionContext(dbConnection drivers.DBDriver, tx interface{}, modelN
 	functions["this"] = func() string {
 		return modelName
 	}
+	functions["macros"] = bindMacros(functions)
 	return functions
 }
 
@@ -44,6 +45,10 @@ func MergePongo2Context(contexts ...pongo2.Context) pongo2.Context {
 			result[key] = value
 		}
 	}
+	// Macros render with the merged variables and functions
+	if _, ok := result["macros"].(macroFunctions); ok {
+		result["macros"] = bindMacros(result)
+	}
 	return result
 }
 
@@ -67,5 +72,11 @@ func FromTaskContextPongo2(ctx *TaskContext) pongo2.Context {
 		return ctx.InstanceUUID
 	}
 
+	options := ctx.runOptions()
+	functions["Var"] = varFunction(options.Vars)
+	functions["RunDate"] = dateFunction(options.RunDate, RUN_DATE_LAYOUT)
+	functions["IntervalStart"] = dateFunction(options.IntervalStart, INTERVAL_LAYOUT)
+	functions["IntervalEnd"] = dateFunction(options.IntervalEnd, INTERVAL_LAYOUT)
+
 	return functions
 }
@@ -0,0 +1,46 @@
+package processing
+
+import (
+	"github.com/go-teal/teal/pkg/drivers"
+	"github.com/rs/zerolog/log"
+)
+
+// applyGrants grants the privileges on the relation in its own transaction.
+// The caller must hold the concurrency lock of the connection.
+func applyGrants(ctx *TaskContext, dbConnection drivers.DBDriver, assetName string, relationName string, grants map[string][]string) error {
+	if len(grants) == 0 {
+		return nil
+	}
+
+	tx, err := dbConnection.Begin()
+	if err != nil {
+		log.Error().Caller().
+			Str("taskId", ctx.TaskID).
+			Str("taskUUID", ctx.TaskUUID).
+			Str("assetName", assetName).
+			Err(err).
+			Msg("Failed to begin transaction")
+		defer dbConnection.Rollback(tx)
+		return err
+	}
+	err = dbConnection.ApplyGrants(tx, relationName, grants)
+	if err != nil {
+		defer dbConnection.Rollback(tx)
+		log.Error().Caller().
+			Str("taskId", ctx.TaskID).
+			Str("taskUUID", ctx.TaskUUID).
+			Str("assetName", assetName).
+			Str("relation", relationName).
+			Err(err).
+			Msg("Failed to apply grants")
+		return err
+	}
+	log.Debug().
+		Str("taskId", ctx.TaskID).
+		Str("taskUUID", ctx.TaskUUID).
+		Str("assetName", assetName).
+		Str("relation", relationName).
+		Any("grants", grants).
+		Msg("Grants applied")
+	return dbConnection.Commit(tx)
+}
@@ -0,0 +1,101 @@
+package processing
+
+import (
+	"fmt"
+
+	pongo2 "github.com/flosch/pongo2/v6"
+	"github.com/go-teal/teal/pkg/core"
+	"github.com/go-teal/teal/pkg/drivers"
+	"github.com/rs/zerolog/log"
+)
+
+// Kinds of hooks, used in logs and errors
+const (
+	HOOK_PRE          = "pre_hook"
+	HOOK_POST         = "post_hook"
+	HOOK_ON_RUN_START = "on_run_start"
+	HOOK_ON_RUN_END   = "on_run_end"
+)
+
+// RunHooks holds the run-level hooks of profile.yaml
+type RunHooks struct {
+	Connection string
+	OnRunStart []string
+	OnRunEnd   []string
+}
+
+// Execute executes the on_run_start or on_run_end hooks of the task
+func (h *RunHooks) Execute(ctx *TaskContext, kind string) error {
+	if h == nil {
+		return nil
+	}
+	hooks := h.OnRunStart
+	if kind == HOOK_ON_RUN_END {
+		hooks = h.OnRunEnd
+	}
+	if len(hooks) == 0 {
+		return nil
+	}
+	dbConnection := core.GetInstance().GetDBConnection(h.Connection)
+	dbConnection.ConcurrencyLock()
+	defer dbConnection.ConcurrencyUnlock()
+	return executeHooks(ctx, dbConnection, "", kind, hooks, make(pongo2.Context))
+}
+
+// executeHooks renders the hooks with the runtime template functions and executes them in one transaction.
+// The caller must hold the concurrency lock of the connection.
+func executeHooks(ctx *TaskContext, dbConnection drivers.DBDriver, assetName string, kind string, hooks []string, functions pongo2.Context) error {
+	if len(hooks) == 0 {
+		return nil
+	}
+
+	tx, err := dbConnection.Begin()
+	if err != nil {
+		log.Error().Caller().
+			Str("taskId", ctx.TaskID).
+			Str("taskUUID", ctx.TaskUUID).
+			Str("assetName", assetName).
+			Err(err).
+			Msg("Failed to begin transaction")
+		defer dbConnection.Rollback(tx)
+		return err
+	}
+
+	context := MergePongo2Context(
+		FromConnectionContext(dbConnection, tx, assetName, functions),
+		FromTaskContextPongo2(ctx),
+	)
+	for i, hook := range hooks {
+		sqlQuery, err := renderSQL(hook, context)
+		if err != nil {
+			defer dbConnection.Rollback(tx)
+			log.Error().Caller().
+				Str("taskId", ctx.TaskID).
+				Str("taskUUID", ctx.TaskUUID).
+				Str("assetName", assetName).
+				Str("sql", hook).
+				Err(err).
+				Msgf("Failed to render %s #%d", kind, i+1)
+			return fmt.Errorf("%s #%d: %w", kind, i+1, err)
+		}
+		err = dbConnection.Exec(tx, sqlQuery)
+		if err != nil {
+			defer dbConnection.Rollback(tx)
+			log.Error().Caller().
+				Str("taskId", ctx.TaskID).
+				Str("taskUUID", ctx.TaskUUID).
+				Str("assetName", assetName).
+				Str("sql", sqlQuery).
+				Err(err).
+				Msgf("Failed to execute %s #%d", kind, i+1)
+			return fmt.Errorf("%s #%d: %w", kind, i+1, err)
+		}
+		log.Debug().
+			Str("taskId", ctx.TaskID).
+			Str("taskUUID", ctx.TaskUUID).
+			Str("assetName", assetName).
+			Str("sql", sqlQuery).
+			Msgf("%s #%d executed", kind, i+1)
+	}
+	return dbConnection.Commit(tx)
+}
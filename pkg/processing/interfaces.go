@@ -1,5 +1,7 @@
 package processing
 
+import "time"
+
 // TaskContext holds runtime context for task execution
 type TaskContext struct {
 	TaskID       string                 // Task identifier from Push method
@@ -7,6 +9,33 @@ type TaskContext struct {
 	InstanceName string                 // DAG instance name
 	InstanceUUID string                 // Unique UUID assigned in constructor
 	Input        map[string]interface{} // Input data from upstream tasks
+	Vars         map[string]interface{} // Vars of profile.yaml with the overrides of the task
+	// RunDate is the logical date of the task, IntervalStart and IntervalEnd bound the data it processes
+	RunDate       time.Time
+	IntervalStart time.Time
+	IntervalEnd   time.Time
+	// FullRefresh drops and recreates the table of an incremental model, IsIncremental() is false
+	FullRefresh bool
+	// SkippedTests are the disabled tests and the tests excluded from the task
+	SkippedTests map[string]bool
+}
+
+// runOptions returns the options of the task, contexts created outside of a DAG task get the defaults
+func (ctx *TaskContext) runOptions() *RunOptions {
+	options := &RunOptions{
+		Vars:          ctx.Vars,
+		RunDate:       ctx.RunDate,
+		IntervalStart: ctx.IntervalStart,
+		IntervalEnd:   ctx.IntervalEnd,
+	}
+	if ctx.Vars != nil && !ctx.RunDate.IsZero() {
+		return options
+	}
+	if resolved, err := options.Resolve(time.Now()); err == nil {
+		return resolved
+	}
+	resolved, _ := (*RunOptions)(nil).Resolve(time.Now())
+	return resolved
 }
 
 // TestStatus represents the status of a test execution
@@ -15,6 +44,7 @@ type TestStatus string
 const (
 	TestStatusSuccess  TestStatus = "SUCCESS"
 	TestStatusFailed   TestStatus = "FAILED"
+	TestStatusWarn     TestStatus = "WARN"
 	TestStatusNotFound TestStatus = "NOT_FOUND"
 )
 
@@ -37,6 +67,7 @@ type Asset interface {
 }
 
 type ModelTesting interface {
-	Execute(ctx *TaskContext) (bool, string, error)
+	// Execute returns SUCCESS, WARN or FAILED, the name of the test and the error of a warned or failed test
+	Execute(ctx *TaskContext) (TestStatus, string, error)
 	GetDescriptor() any
 }
@@ -0,0 +1,183 @@
+package processing
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	pongo2 "github.com/flosch/pongo2/v6"
+)
+
+// macroPrologue turns off HTML escaping, macros render SQL. It is kept on the first line so that
+// the lines of errors match the lines of the macro file.
+const macroPrologue = "{% autoescape off %}"
+
+// macroOutputMarker separates the text around the macro definitions from the output of the call
+const macroOutputMarker = "<<<teal-macro-output>>>"
+
+var macroNamePattern = regexp.MustCompile(`\{%-?\s*macro\s+([A-Za-z_][A-Za-z0-9_]*)\s*\(`)
+
+type macroFile struct {
+	fileName string
+	source   string
+}
+
+// macroRegistry holds the macros of assets/macros, registered by the generated code
+var macroRegistry = struct {
+	sync.RWMutex
+	macros    map[string]*macroFile
+	templates map[string]*pongo2.Template
+}{
+	macros:    make(map[string]*macroFile),
+	templates: make(map[string]*pongo2.Template),
+}
+
+// ParseMacroFile checks the syntax of a macro file and returns the names of its macros.
+// Errors refer to the line of the macro file.
+func ParseMacroFile(fileName string, source string) ([]string, error) {
+	if _, err := pongo2.FromString(macroPrologue + source + "{% endautoescape %}"); err != nil {
+		return nil, macroError(fileName, source, err)
+	}
+	var names []string
+	for _, match := range macroNamePattern.FindAllStringSubmatch(source, -1) {
+		names = append(names, match[1])
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("%s: no macros found", fileName)
+	}
+	return names, nil
+}
+
+// RegisterMacros makes the macros of the files, keyed by file name, callable as {{ macros.name(...) }}
+func RegisterMacros(files map[string]string) error {
+	fileNames := make([]string, 0, len(files))
+	for fileName := range files {
+		fileNames = append(fileNames, fileName)
+	}
+	sort.Strings(fileNames)
+
+	macroRegistry.Lock()
+	defer macroRegistry.Unlock()
+	for _, fileName := range fileNames {
+		source := files[fileName]
+		names, err := ParseMacroFile(fileName, source)
+		if err != nil {
+			return err
+		}
+		file := &macroFile{fileName: fileName, source: source}
+		for _, name := range names {
+			if registered, ok := macroRegistry.macros[name]; ok && registered.fileName != fileName {
+				return fmt.Errorf("%s: macro %s is already defined in %s", fileName, name, registered.fileName)
+			}
+			macroRegistry.macros[name] = file
+		}
+	}
+	macroRegistry.templates = make(map[string]*pongo2.Template)
+	return nil
+}
+
+// MustRegisterMacros is RegisterMacros for the generated code, it panics on error
+func MustRegisterMacros(files map[string]string) {
+	if err := RegisterMacros(files); err != nil {
+		panic(err)
+	}
+}
+
+// macroFunctions is the "macros" variable of templates
+type macroFunctions map[string]interface{}
+
+// bindMacros returns the macros, they render with the variables and functions of context
+func bindMacros(context pongo2.Context) macroFunctions {
+	macroRegistry.RLock()
+	defer macroRegistry.RUnlock()
+	functions := make(macroFunctions, len(macroRegistry.macros))
+	for name := range macroRegistry.macros {
+		macroName := name
+		functions[macroName] = func(args ...*pongo2.Value) (*pongo2.Value, error) {
+			output, err := callMacro(macroName, args, context)
+			if err != nil {
+				return nil, err
+			}
+			return pongo2.AsSafeValue(output), nil
+		}
+	}
+	return functions
+}
+
+// callMacro renders the macro file followed by the call, the output of the call is what follows the marker
+func callMacro(name string, args []*pongo2.Value, context pongo2.Context) (string, error) {
+	macroRegistry.RLock()
+	file := macroRegistry.macros[name]
+	macroRegistry.RUnlock()
+
+	callContext := make(pongo2.Context, len(context)+len(args))
+	for key, value := range context {
+		callContext[key] = value
+	}
+	argNames := make([]string, len(args))
+	for i, arg := range args {
+		argNames[i] = fmt.Sprintf("teal_macro_arg_%d", i)
+		callContext[argNames[i]] = arg.Interface()
+	}
+
+	template, err := getMacroTemplate(file, name, argNames)
+	if err != nil {
+		return "", err
+	}
+	output, err := template.Execute(callContext)
+	if err != nil {
+		return "", fmt.Errorf("macro %s: %w", name, macroError(file.fileName, file.source, err))
+	}
+	return output[strings.LastIndex(output, macroOutputMarker)+len(macroOutputMarker):], nil
+}
+
+// getMacroTemplate compiles the call of a macro once per number of arguments
+func getMacroTemplate(file *macroFile, name string, argNames []string) (*pongo2.Template, error) {
+	key := fmt.Sprintf("%s/%d", name, len(argNames))
+	macroRegistry.RLock()
+	template, ok := macroRegistry.templates[key]
+	macroRegistry.RUnlock()
+	if ok {
+		return template, nil
+	}
+
+	source := macroPrologue + file.source + macroOutputMarker +
+		"{{ " + name + "(" + strings.Join(argNames, ", ") + ") }}{% endautoescape %}"
+	template, err := pongo2.FromString(source)
+	if err != nil {
+		return nil, macroError(file.fileName, file.source, err)
+	}
+	macroRegistry.Lock()
+	macroRegistry.templates[key] = template
+	macroRegistry.Unlock()
+	return template, nil
+}
+
+// templateErrorLocation matches the location of pongo2 errors, errors of nested calls are flattened into the message
+var templateErrorLocation = regexp.MustCompile(`\| Line (\d+) Col (\d+)(?: near '[^']*')?\] `)
+
+// macroError refers a pongo2 error to the line and column of the macro file
+func macroError(fileName string, source string, err error) error {
+	message := err.Error()
+	locations := templateErrorLocation.FindAllStringSubmatchIndex(message, -1)
+	if len(locations) == 0 {
+		return fmt.Errorf("%s: %s", fileName, message)
+	}
+	// The innermost error is the last one
+	location := locations[len(locations)-1]
+	line, _ := strconv.Atoi(message[location[2]:location[3]])
+	column, _ := strconv.Atoi(message[location[4]:location[5]])
+	message = message[location[1]:]
+	if line == 1 {
+		column -= len(macroPrologue)
+	}
+	lines := strings.Split(source, "\n")
+	if line > len(lines) || column <= 0 || (line == len(lines) && column > len(lines[len(lines)-1])) {
+		// The error is in the call itself
+		return fmt.Errorf("%s: %s", fileName, message)
+	}
+	return fmt.Errorf("%s:%d:%d: %s", fileName, line, column, message)
+}
@@ -0,0 +1,49 @@
+package processing
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	pongo2 "github.com/flosch/pongo2/v6"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMacrosRenderWithArgumentsAndContext(t *testing.T) {
+	require.NoError(t, RegisterMacros(map[string]string{
+		"assets/macros/dedup.sql": `-- keeps the last row per key
+{% macro dedup(relation, key, order_by="updated_at") %}
+select * from {{ relation }}
+qualify row_number() over (partition by {{ key }} order by {{ order_by }} desc) = 1 -- {{ TaskID() }}
+{% endmacro %}
+`,
+		"assets/macros/broken.sql": `{% macro broken(relation) %}
+select *
+from {{ Fail(relation) }}
+{% endmacro %}`,
+	}))
+
+	context := MergePongo2Context(
+		pongo2.Context{"macros": bindMacros(pongo2.Context{})},
+		pongo2.Context{
+			"TaskID": func() string { return "task-1" },
+			"Fail":   func(string) (string, error) { return "", errors.New("boom") },
+		},
+	)
+	tpl, err := pongo2.FromString(`{{ macros.dedup("staging.orders", "id") }}`)
+	require.NoError(t, err)
+	output, err := tpl.Execute(context)
+	require.NoError(t, err)
+	assert.Equal(t, "select * from staging.orders\nqualify row_number() over (partition by id order by updated_at desc) = 1 -- task-1", strings.TrimSpace(output))
+
+	_, err = ParseMacroFile("assets/macros/bad.sql", "{% macro bad(x) %}\n{{ x \n{% endmacro %}")
+	require.Error(t, err)
+	assert.True(t, strings.HasPrefix(err.Error(), "assets/macros/bad.sql:2:"), err.Error())
+
+	tpl, err = pongo2.FromString(`{{ macros.broken("staging.orders") }}`)
+	require.NoError(t, err)
+	_, err = tpl.Execute(context)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "assets/macros/broken.sql:3:")
+}
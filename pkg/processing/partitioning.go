@@ -0,0 +1,98 @@
+package processing
+
+import (
+	"github.com/go-teal/teal/pkg/configs"
+	"github.com/rs/zerolog/log"
+)
+
+// materializePartitioned creates or updates a partitioned table in one transaction.
+// The table materialization truncates the whole table, the incremental one appends
+// to the partitions of the new rows or replaces them with replace_partitions.
+func (s *SQLModelAsset) materializePartitioned(ctx *TaskContext, isTableExists bool) error {
+	dbConnection := s.getDBConnection()
+	partitionBy := s.descriptor.ModelProfile.PartitionBy
+	isIncremental := isTableExists && s.descriptor.ModelProfile.Materialization == configs.MAT_INCREMENTAL
+
+	tx, err := dbConnection.Begin()
+	if err != nil {
+		log.Error().Caller().
+			Str("taskId", ctx.TaskID).
+			Str("taskUUID", ctx.TaskUUID).
+			Str("assetName", s.descriptor.Name).
+			Err(err).
+			Msg("Failed to begin transaction")
+		defer dbConnection.Rollback(tx)
+		return err
+	}
+
+	s.functions["IsIncremental"] = func() bool {
+		return isIncremental
+	}
+	context := MergePongo2Context(
+		FromConnectionContext(dbConnection, tx, s.descriptor.Name, s.functions),
+		FromTaskContextPongo2(ctx),
+	)
+	sqlQuery, err := renderSQL(s.descriptor.RawSQL, context)
+	if err != nil {
+		defer dbConnection.Rollback(tx)
+		log.Error().Caller().Stack().
+			Str("taskId", ctx.TaskID).
+			Str("taskUUID", ctx.TaskUUID).
+			Str("assetName", s.descriptor.Name).
+			Str("sql", s.descriptor.RawSQL).
+			Err(err).
+			Msg("Failed to render template")
+		return err
+	}
+
+	if !isTableExists {
+		err = dbConnection.CreatePartitionedTable(tx, s.descriptor.Name, sqlQuery, partitionBy)
+		if err == nil && s.descriptor.CreateIndexesSQL != "" {
+			err = dbConnection.Exec(tx, s.descriptor.CreateIndexesSQL)
+		}
+		if err != nil {
+			defer dbConnection.Rollback(tx)
+			log.Error().Caller().
+				Str("taskId", ctx.TaskID).
+				Str("taskUUID", ctx.TaskUUID).
+				Str("assetName", s.descriptor.Name).
+				Err(err).
+				Msg("Failed to create partitioned table")
+			return err
+		}
+	} else if s.descriptor.ModelProfile.Materialization == configs.MAT_TABLE {
+		err = dbConnection.Exec(tx, "truncate table "+s.descriptor.Name)
+		if err != nil {
+			defer dbConnection.Rollback(tx)
+			log.Error().Caller().
+				Str("taskId", ctx.TaskID).
+				Str("taskUUID", ctx.TaskUUID).
+				Str("assetName", s.descriptor.Name).
+				Err(err).
+				Msg("Failed to truncate table")
+			return err
+		}
+	}
+
+	replace := isIncremental && partitionBy.ReplacePartitions
+	partitions, err := dbConnection.InsertPartitions(tx, s.descriptor.Name, sqlQuery, partitionBy, replace)
+	if err != nil {
+		defer dbConnection.Rollback(tx)
+		log.Error().Caller().
+			Str("taskId", ctx.TaskID).
+			Str("taskUUID", ctx.TaskUUID).
+			Str("assetName", s.descriptor.Name).
+			Str("sql", sqlQuery).
+			Err(err).
+			Msg("Failed to insert into partitions")
+		return err
+	}
+	log.Info().
+		Str("taskId", ctx.TaskID).
+		Str("taskUUID", ctx.TaskUUID).
+		Str("assetName", s.descriptor.Name).
+		Strs("partitions", partitions).
+		Bool("replaced", replace).
+		Msg("Partitions updated")
+	return dbConnection.Commit(tx)
+}
@@ -2,9 +2,13 @@ package processing
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 	"sync"
 
+	"github.com/go-teal/gota/dataframe"
 	"github.com/go-teal/teal/pkg/configs"
+	"github.com/go-teal/teal/pkg/core"
 	"github.com/go-teal/teal/pkg/models"
 	"github.com/rs/zerolog/log"
 )
@@ -14,6 +18,7 @@ type ExecutorFunc func(ctx *TaskContext, modelProfile *configs.ModelProfile) (in
 // GO singletone
 type GlobalExecutors struct {
 	Execurots map[string]ExecutorFunc
+	mu        sync.RWMutex
 }
 
 var globalExecutors *GlobalExecutors
@@ -29,14 +34,86 @@ func GetExecutors() *GlobalExecutors {
 	return globalExecutors
 }
 
+// RegisterExecutor registers the executor of the raw asset "<stage>.<asset name>".
+// Returns an error if the name is empty, the executor is nil or the asset already has an executor.
+func RegisterExecutor(assetName string, executor ExecutorFunc) error {
+	if assetName == "" {
+		return fmt.Errorf("executor name is empty")
+	}
+	if executor == nil {
+		return fmt.Errorf("executor %s is nil", assetName)
+	}
+	executors := GetExecutors()
+	executors.mu.Lock()
+	defer executors.mu.Unlock()
+	if _, ok := executors.Execurots[assetName]; ok {
+		return fmt.Errorf("executor %s is already registered", assetName)
+	}
+	executors.Execurots[assetName] = executor
+	return nil
+}
+
+// MustRegisterExecutor registers the executor like RegisterExecutor and panics on error
+func MustRegisterExecutor(assetName string, executor ExecutorFunc) {
+	if err := RegisterExecutor(assetName, executor); err != nil {
+		panic(err)
+	}
+}
+
+// ValidateExecutors checks that every raw asset of the project has a registered executor.
+// Executors registered for unknown assets are logged as warnings.
+func ValidateExecutors(projectAssets map[string]Asset) error {
+	executors := GetExecutors()
+	executors.mu.RLock()
+	defer executors.mu.RUnlock()
+
+	var missing []string
+	for name, asset := range projectAssets {
+		if _, ok := asset.(*RawModelAsset); !ok {
+			continue
+		}
+		if _, ok := executors.Execurots[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	for name := range executors.Execurots {
+		if asset, ok := projectAssets[name]; !ok {
+			log.Warn().Str("assetName", name).Msg("Executor is registered for an unknown asset")
+		} else if _, ok := asset.(*RawModelAsset); !ok {
+			log.Warn().Str("assetName", name).Msg("Executor is registered for an asset which is not raw")
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return fmt.Errorf("executors are not registered for raw assets: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
 type RawModelAsset struct {
 	descriptor *models.RawModelDescriptor
+	mu         sync.Mutex
+	// lastResult is the DataFrame of the last execution tested by RunTests,
+	// nil when the execution failed or returned no DataFrame
+	lastResult *dataframe.DataFrame
 }
 
 // Execute implements Asset.
 func (r *RawModelAsset) Execute(ctx *TaskContext) (interface{}, error) {
-	if f, ok := GetExecutors().Execurots[r.descriptor.Name]; ok {
-		return f(ctx, r.descriptor.ModelProfile)
+	executors := GetExecutors()
+	executors.mu.RLock()
+	f, ok := executors.Execurots[r.descriptor.Name]
+	executors.mu.RUnlock()
+	if ok {
+		data, err := f(ctx, r.descriptor.ModelProfile)
+		df, _ := data.(*dataframe.DataFrame)
+		if err != nil {
+			df = nil
+		}
+		r.mu.Lock()
+		r.lastResult = df
+		r.mu.Unlock()
+		return data, err
 	} else {
 		return nil, fmt.Errorf("executor %v is not registered", r.descriptor.Name)
 	}
@@ -64,9 +141,129 @@ func (r *RawModelAsset) GetUpstreams() []string {
 }
 
 // RunTests implements Asset.
+// Tests of a data framed raw asset query the returned DataFrame persisted to the relation tmp_<stage>_<name>__test
+// while the tests run, tests of other raw assets query the table the executor writes to.
 func (r *RawModelAsset) RunTests(ctx *TaskContext, testsMap map[string]ModelTesting) []TestResult {
-	log.Warn().Str("taskId", ctx.TaskID).Str("taskUUID", ctx.TaskUUID).Msg("Raw Model Asset does not support tests")
-	return []TestResult{}
+	tests := r.descriptor.ModelProfile.Tests
+	if len(tests) == 0 {
+		return []TestResult{}
+	}
+	if !r.descriptor.ModelProfile.IsDataFramed {
+		return runModelTests(ctx, r.descriptor.Name, tests, testsMap)
+	}
+	var results []TestResult
+	err := r.WithPersistedResult(ctx, func() {
+		results = runModelTests(ctx, r.descriptor.Name, tests, testsMap)
+	})
+	if err != nil {
+		log.Error().Caller().
+			Str("taskId", ctx.TaskID).
+			Str("taskUUID", ctx.TaskUUID).
+			Str("assetName", r.descriptor.Name).
+			Err(err).
+			Msg("Failed to persist the DataFrame for tests")
+		results = make([]TestResult, 0, len(tests))
+		for _, testConfig := range tests {
+			results = append(results, TestResult{
+				TestName: testConfig.Name,
+				Status:   TestStatusFailed,
+				Message:  testConfig.Name,
+				Error:    err,
+			})
+		}
+	}
+	return results
+}
+
+// WithPersistedResult writes the DataFrame of the last execution to the relation the tests of the asset read
+// (see configs.ModelProfile.GetTestTempName), calls run and drops the relation
+func (r *RawModelAsset) WithPersistedResult(ctx *TaskContext, run func()) error {
+	if err := r.persistResult(ctx); err != nil {
+		return err
+	}
+	defer r.dropResult(ctx)
+	run()
+	return nil
+}
+
+// persistResult replaces the test relation with the DataFrame of the last execution.
+// The relation is a regular table, so the tests see it from any connection of the pool.
+func (r *RawModelAsset) persistResult(ctx *TaskContext) error {
+	r.mu.Lock()
+	df := r.lastResult
+	r.mu.Unlock()
+	if df == nil {
+		return fmt.Errorf("raw asset %s did not return a DataFrame to test", r.descriptor.Name)
+	}
+
+	dbConnection := core.GetInstance().GetDBConnection(r.descriptor.ModelProfile.Connection)
+	dbConnection.ConcurrencyLock()
+	defer dbConnection.ConcurrencyUnlock()
+
+	relationName := r.descriptor.ModelProfile.GetTestTempName()
+	stageName := relationName + "__df"
+	tx, err := dbConnection.Begin()
+	if err != nil {
+		defer dbConnection.Rollback(tx)
+		return err
+	}
+	statements := []string{
+		fmt.Sprintf("drop table if exists %s", stageName),
+		fmt.Sprintf("drop table if exists %s", relationName),
+	}
+	for _, statement := range statements {
+		if err = dbConnection.Exec(tx, statement); err != nil {
+			defer dbConnection.Rollback(tx)
+			return err
+		}
+	}
+	if err = dbConnection.PersistDataFrame(tx, stageName, df); err != nil {
+		defer dbConnection.Rollback(tx)
+		return err
+	}
+	statements = []string{
+		fmt.Sprintf("create table %s as select * from %s", relationName, stageName),
+		fmt.Sprintf("drop table %s", stageName),
+	}
+	for _, statement := range statements {
+		if err = dbConnection.Exec(tx, statement); err != nil {
+			defer dbConnection.Rollback(tx)
+			return err
+		}
+	}
+	log.Debug().
+		Str("taskId", ctx.TaskID).
+		Str("taskUUID", ctx.TaskUUID).
+		Str("assetName", r.descriptor.Name).
+		Str("relation", relationName).
+		Msg("DataFrame persisted for tests")
+	return dbConnection.Commit(tx)
+}
+
+// dropResult drops the test relation, a failure is logged only: the next persistResult replaces the relation
+func (r *RawModelAsset) dropResult(ctx *TaskContext) {
+	dbConnection := core.GetInstance().GetDBConnection(r.descriptor.ModelProfile.Connection)
+	dbConnection.ConcurrencyLock()
+	defer dbConnection.ConcurrencyUnlock()
+
+	relationName := r.descriptor.ModelProfile.GetTestTempName()
+	tx, err := dbConnection.Begin()
+	if err == nil {
+		if err = dbConnection.Exec(tx, fmt.Sprintf("drop table if exists %s", relationName)); err == nil {
+			err = dbConnection.Commit(tx)
+		} else {
+			dbConnection.Rollback(tx)
+		}
+	}
+	if err != nil {
+		log.Warn().
+			Str("taskId", ctx.TaskID).
+			Str("taskUUID", ctx.TaskUUID).
+			Str("assetName", r.descriptor.Name).
+			Str("relation", relationName).
+			Err(err).
+			Msg("Failed to drop the DataFrame of the tests")
+	}
 }
 
 func InitRawModelAsset(descriptor *models.RawModelDescriptor) Asset {
@@ -0,0 +1,120 @@
+package processing
+
+import (
+	"testing"
+
+	"github.com/go-teal/gota/dataframe"
+	"github.com/go-teal/gota/series"
+	"github.com/go-teal/teal/pkg/configs"
+	"github.com/go-teal/teal/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func noopExecutor(ctx *TaskContext, modelProfile *configs.ModelProfile) (interface{}, error) {
+	return nil, nil
+}
+
+func TestRegisterExecutorReportsDuplicates(t *testing.T) {
+	defer delete(GetExecutors().Execurots, "staging.once")
+
+	assert.NoError(t, RegisterExecutor("staging.once", noopExecutor))
+	assert.EqualError(t, RegisterExecutor("staging.once", noopExecutor), "executor staging.once is already registered")
+	assert.Error(t, RegisterExecutor("staging.nil", nil))
+	assert.Panics(t, func() { MustRegisterExecutor("staging.once", noopExecutor) })
+}
+
+func TestValidateExecutors(t *testing.T) {
+	defer delete(GetExecutors().Execurots, "staging.registered")
+	MustRegisterExecutor("staging.registered", noopExecutor)
+
+	projectAssets := map[string]Asset{
+		"staging.registered": InitRawModelAsset(&models.RawModelDescriptor{Name: "staging.registered"}),
+		"staging.missing_b":  InitRawModelAsset(&models.RawModelDescriptor{Name: "staging.missing_b"}),
+		"staging.missing_a":  InitRawModelAsset(&models.RawModelDescriptor{Name: "staging.missing_a"}),
+		"staging.sql_model":  InitSQLModelAsset(&models.SQLModelDescriptor{Name: "staging.sql_model"}),
+	}
+
+	assert.EqualError(t, ValidateExecutors(projectAssets), "executors are not registered for raw assets: staging.missing_a, staging.missing_b")
+
+	delete(projectAssets, "staging.missing_a")
+	delete(projectAssets, "staging.missing_b")
+	assert.NoError(t, ValidateExecutors(projectAssets))
+}
+
+func TestRawAssetTestsFailWithoutDataFrame(t *testing.T) {
+	asset := InitRawModelAsset(&models.RawModelDescriptor{
+		Name: "staging.extract",
+		ModelProfile: &configs.ModelProfile{
+			Name:         "extract",
+			Stage:        "staging",
+			IsDataFramed: true,
+			Tests:        []*configs.TestProfile{{Name: "staging.test_extract_not_empty"}},
+		},
+	})
+
+	results := asset.RunTests(&TaskContext{}, map[string]ModelTesting{})
+
+	assert.Len(t, results, 1)
+	assert.Equal(t, TestStatusFailed, results[0].Status)
+	assert.EqualError(t, results[0].Error, "raw asset staging.extract did not return a DataFrame to test")
+}
+
+// recordingTest records the statements executed before the test
+type recordingTest struct {
+	driver *recordingDriver
+	seen   []string
+}
+
+func (r *recordingTest) Execute(ctx *TaskContext) (TestStatus, string, error) {
+	r.seen = append([]string{}, r.driver.statements...)
+	return TestStatusSuccess, "staging.test_extract_not_empty", nil
+}
+
+func (r *recordingTest) GetDescriptor() any {
+	return nil
+}
+
+func TestRawAssetTestsReadDedicatedRelation(t *testing.T) {
+	driver := useRecordingDriver("raw_tests")
+	df := dataframe.New(series.New([]int{1, 2}, series.Int, "id"))
+	results := []interface{}{&df, nil}
+	GetExecutors().Execurots["staging.extract"] = func(ctx *TaskContext, modelProfile *configs.ModelProfile) (interface{}, error) {
+		result := results[0]
+		results = results[1:]
+		return result, nil
+	}
+	defer delete(GetExecutors().Execurots, "staging.extract")
+	asset := InitRawModelAsset(&models.RawModelDescriptor{
+		Name: "staging.extract",
+		ModelProfile: &configs.ModelProfile{
+			Name:         "extract",
+			Stage:        "staging",
+			Connection:   "raw_tests",
+			IsDataFramed: true,
+			Tests:        []*configs.TestProfile{{Name: "staging.test_extract_not_empty"}},
+		},
+	})
+	test := &recordingTest{driver: driver}
+	testsMap := map[string]ModelTesting{"staging.test_extract_not_empty": test}
+
+	_, err := asset.Execute(&TaskContext{})
+	assert.NoError(t, err)
+	testResults := asset.RunTests(&TaskContext{}, testsMap)
+	assert.Equal(t, TestStatusSuccess, testResults[0].Status)
+	// The tests read tmp_<stage>_<name>__test, not the tmp_<stage>_<name> of persist_inputs, which is dropped after them
+	assert.Equal(t, []string{
+		"drop table if exists tmp_staging_extract__test__df",
+		"drop table if exists tmp_staging_extract__test",
+		"persist tmp_staging_extract__test__df",
+		"create table tmp_staging_extract__test as select * from tmp_staging_extract__test__df",
+		"drop table tmp_staging_extract__test__df",
+	}, test.seen)
+	assert.Equal(t, "drop table if exists tmp_staging_extract__test", driver.statements[len(driver.statements)-1])
+
+	// The next execution returns no DataFrame, the tests must not see the previous one
+	_, err = asset.Execute(&TaskContext{})
+	assert.NoError(t, err)
+	testResults = asset.RunTests(&TaskContext{}, testsMap)
+	assert.Equal(t, TestStatusFailed, testResults[0].Status)
+	assert.EqualError(t, testResults[0].Error, "raw asset staging.extract did not return a DataFrame to test")
+}
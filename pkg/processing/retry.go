@@ -0,0 +1,144 @@
+package processing
+
+import (
+	"errors"
+	"time"
+
+	"github.com/go-teal/teal/pkg/configs"
+	"github.com/go-teal/teal/pkg/core"
+	"github.com/go-teal/teal/pkg/models"
+	"github.com/rs/zerolog/log"
+)
+
+// DEFAULT_RETRY_DELAY is used when the profile has retries but no retry_delay
+const DEFAULT_RETRY_DELAY = time.Second
+
+// RetryableError marks an error of a raw executor as transient,
+// the asset is executed again while it has retries left
+type RetryableError struct {
+	Err error
+}
+
+func (e *RetryableError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *RetryableError) Unwrap() error {
+	return e.Err
+}
+
+// Retryable wraps the error into RetryableError
+func Retryable(err error) error {
+	return &RetryableError{Err: err}
+}
+
+// materializationError marks the errors of the materialization step of a SQL model,
+// the failed transaction of the step is rolled back and the earlier steps changed nothing the repeat would apply twice
+type materializationError struct {
+	err error
+}
+
+func (e *materializationError) Error() string {
+	return e.err.Error()
+}
+
+func (e *materializationError) Unwrap() error {
+	return e.err
+}
+
+// ExecuteWithRetries executes the asset and executes it again after retryable errors
+// according to the retries, retry_delay and retry_backoff of its profile.
+// Only executions leaving no changes behind are repeated, see isRepeatable.
+// onRetry (optional) is called before every retry with the number of the next attempt.
+// Returns the result of the last attempt and the number of attempts made.
+func ExecuteWithRetries(ctx *TaskContext, asset Asset, onRetry func(attempt int, err error)) (interface{}, int, error) {
+	modelProfile := assetModelProfile(asset)
+	attempt := 1
+	for {
+		data, err := asset.Execute(ctx)
+		if err == nil || modelProfile == nil || attempt > modelProfile.Retries || !isRetryableError(modelProfile.Connection, err) {
+			return data, attempt, err
+		}
+		if !isRepeatable(asset, err) {
+			log.Warn().
+				Str("taskId", ctx.TaskID).
+				Str("taskUUID", ctx.TaskUUID).
+				Str("assetName", asset.GetName()).
+				Int("attempt", attempt).
+				Err(err).
+				Msg("Retryable error left changes behind, the asset is not executed again")
+			return data, attempt, err
+		}
+		delay := retryDelay(modelProfile, attempt)
+		log.Warn().
+			Str("taskId", ctx.TaskID).
+			Str("taskUUID", ctx.TaskUUID).
+			Str("assetName", asset.GetName()).
+			Int("attempt", attempt).
+			Int("retries", modelProfile.Retries).
+			Dur("delay", delay).
+			Err(err).
+			Msg("Retryable error, executing the asset again")
+		attempt++
+		if onRetry != nil {
+			onRetry(attempt, err)
+		}
+		time.Sleep(delay)
+	}
+}
+
+// retryDelay returns the pause after the failed attempt (starting from 1)
+func retryDelay(modelProfile *configs.ModelProfile, attempt int) time.Duration {
+	delay := DEFAULT_RETRY_DELAY
+	if modelProfile.RetryDelay != "" {
+		if parsed, err := time.ParseDuration(modelProfile.RetryDelay); err == nil {
+			delay = parsed
+		}
+	}
+	if modelProfile.RetryBackoff > 1 {
+		for i := 1; i < attempt; i++ {
+			delay = time.Duration(float64(delay) * modelProfile.RetryBackoff)
+		}
+	}
+	return delay
+}
+
+func isRetryableError(connection string, err error) bool {
+	var retryable *RetryableError
+	if errors.As(err, &retryable) {
+		return true
+	}
+	return connection != "" && core.GetInstance().GetDBConnection(connection).IsRetryableError(err)
+}
+
+// isRepeatable reports whether the failed execution can be repeated without applying its side effects twice:
+// atomic SQL models rolled back entirely, repeatable materializations failed in their own transaction,
+// seeds loaded in a single transaction and raw executors declaring the error retryable with Retryable
+func isRepeatable(asset Asset, err error) bool {
+	switch descriptor := asset.GetDescriptor().(type) {
+	case *models.SQLModelDescriptor:
+		if errors.Is(err, ErrAtomicRollback) {
+			return !errors.Is(err, errRollbackFailed)
+		}
+		var materializationErr *materializationError
+		return descriptor.ModelProfile != nil && descriptor.ModelProfile.IsRepeatableMaterialization() && errors.As(err, &materializationErr)
+	case *models.SeedDescriptor:
+		return true
+	case *models.RawModelDescriptor:
+		var retryable *RetryableError
+		return errors.As(err, &retryable)
+	}
+	return false
+}
+
+func assetModelProfile(asset Asset) *configs.ModelProfile {
+	switch descriptor := asset.GetDescriptor().(type) {
+	case *models.SQLModelDescriptor:
+		return descriptor.ModelProfile
+	case *models.RawModelDescriptor:
+		return descriptor.ModelProfile
+	case *models.SeedDescriptor:
+		return descriptor.ModelProfile
+	}
+	return nil
+}
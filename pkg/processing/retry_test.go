@@ -0,0 +1,116 @@
+package processing
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-teal/teal/pkg/configs"
+	"github.com/go-teal/teal/pkg/drivers"
+	"github.com/go-teal/teal/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExecuteWithRetries(t *testing.T) {
+	calls := 0
+	GetExecutors().Execurots["staging.flaky"] = func(ctx *TaskContext, modelProfile *configs.ModelProfile) (interface{}, error) {
+		calls++
+		if calls < 3 {
+			return nil, Retryable(errors.New("lock timeout"))
+		}
+		return "done", nil
+	}
+	defer delete(GetExecutors().Execurots, "staging.flaky")
+
+	asset := InitRawModelAsset(&models.RawModelDescriptor{
+		Name:         "staging.flaky",
+		ModelProfile: &configs.ModelProfile{Retries: 2, RetryDelay: "1ms"},
+	})
+	retried := []int{}
+	data, attempts, err := ExecuteWithRetries(&TaskContext{}, asset, func(attempt int, err error) {
+		retried = append(retried, attempt)
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "done", data)
+	assert.Equal(t, 3, attempts)
+	assert.Equal(t, []int{2, 3}, retried)
+}
+
+func TestExecuteWithRetriesStopsOnPermanentError(t *testing.T) {
+	calls := 0
+	GetExecutors().Execurots["staging.broken"] = func(ctx *TaskContext, modelProfile *configs.ModelProfile) (interface{}, error) {
+		calls++
+		return nil, errors.New("division by zero")
+	}
+	defer delete(GetExecutors().Execurots, "staging.broken")
+
+	asset := InitRawModelAsset(&models.RawModelDescriptor{
+		Name:         "staging.broken",
+		ModelProfile: &configs.ModelProfile{Retries: 5, RetryDelay: "1ms"},
+	})
+	_, attempts, err := ExecuteWithRetries(&TaskContext{}, asset, nil)
+
+	assert.Error(t, err)
+	assert.Equal(t, 1, attempts)
+	assert.Equal(t, 1, calls)
+}
+
+func TestIsRepeatable(t *testing.T) {
+	failure := Retryable(errors.New("deadlock detected"))
+	sqlAsset := InitSQLModelAsset(&models.SQLModelDescriptor{Name: "dds.orders", ModelProfile: &configs.ModelProfile{Materialization: configs.MAT_TABLE}})
+	customAsset := InitSQLModelAsset(&models.SQLModelDescriptor{Name: "dds.cleanup", ModelProfile: &configs.ModelProfile{Materialization: configs.MAT_CUSTOM}})
+	hookedAsset := InitSQLModelAsset(&models.SQLModelDescriptor{Name: "dds.orders", ModelProfile: &configs.ModelProfile{Materialization: configs.MAT_TABLE, PreHooks: []string{"analyze staging.orders"}}})
+	seedAsset := InitSeedAsset(&models.SeedDescriptor{Name: "staging.countries"})
+	rawAsset := InitRawModelAsset(&models.RawModelDescriptor{Name: "staging.flaky"})
+
+	rolledBack := runAtomic(&txRecorder{}, func(dbConnection drivers.DBDriver) error {
+		return failure
+	})
+	notRolledBack := runAtomic(&failingRollback{}, func(dbConnection drivers.DBDriver) error {
+		return failure
+	})
+
+	assert.True(t, isRepeatable(sqlAsset, rolledBack))
+	assert.False(t, isRepeatable(sqlAsset, notRolledBack))
+	assert.False(t, isRepeatable(sqlAsset, failure))
+	assert.True(t, isRepeatable(sqlAsset, &materializationError{err: failure}))
+	assert.False(t, isRepeatable(customAsset, &materializationError{err: failure}))
+	assert.False(t, isRepeatable(hookedAsset, &materializationError{err: failure}))
+	assert.True(t, isRepeatable(seedAsset, failure))
+	assert.True(t, isRepeatable(rawAsset, failure))
+	assert.False(t, isRepeatable(rawAsset, errors.New("deadlock detected")))
+}
+
+func TestExecuteWithRetriesRepeatsFailedMaterialization(t *testing.T) {
+	driver := useRecordingDriver("retry_materialization")
+	driver.failures["create table"] = Retryable(errors.New("could not serialize access due to concurrent update"))
+	asset := InitSQLModelAsset(&models.SQLModelDescriptor{
+		Name:           "dds.orders",
+		CreateTableSQL: "create table dds.orders as (select id from staging.orders)",
+		ModelProfile: &configs.ModelProfile{
+			Connection:      "retry_materialization",
+			Materialization: configs.MAT_TABLE,
+			Retries:         2,
+			RetryDelay:      "1ms",
+		},
+	})
+
+	_, attempts, err := ExecuteWithRetries(&TaskContext{}, asset, func(attempt int, err error) {
+		delete(driver.failures, "create table")
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, attempts)
+	assert.Equal(t, []string{
+		"create table dds.orders as (select id from staging.orders)",
+		"create table dds.orders as (select id from staging.orders)",
+	}, driver.statements)
+}
+
+func TestRetryDelay(t *testing.T) {
+	profile := &configs.ModelProfile{RetryDelay: "100ms", RetryBackoff: 2}
+	assert.Equal(t, 100*time.Millisecond, retryDelay(profile, 1))
+	assert.Equal(t, 200*time.Millisecond, retryDelay(profile, 2))
+	assert.Equal(t, 400*time.Millisecond, retryDelay(profile, 3))
+	assert.Equal(t, DEFAULT_RETRY_DELAY, retryDelay(&configs.ModelProfile{}, 3))
+}
@@ -0,0 +1,163 @@
+package processing
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	// RUN_DATE_LAYOUT is the default layout of RunDate()
+	RUN_DATE_LAYOUT = "2006-01-02"
+	// INTERVAL_LAYOUT is the default layout of IntervalStart() and IntervalEnd(), a SQL timestamp literal
+	INTERVAL_LAYOUT = "2006-01-02 15:04:05"
+	// DEFAULT_INTERVAL is the length of the data interval when only its start is known
+	DEFAULT_INTERVAL = 24 * time.Hour
+)
+
+// RunOptions are the options of a single task of a DAG
+type RunOptions struct {
+	// Vars override the vars of profile.yaml, every name must be declared there
+	Vars map[string]interface{}
+	// RunDate is the logical date of the task, today (UTC) by default
+	RunDate time.Time
+	// IntervalStart and IntervalEnd bound the data the task processes, [start, end).
+	// The interval starts at the run date and lasts a day by default.
+	IntervalStart time.Time
+	IntervalEnd   time.Time
+	// FullRefresh rebuilds the incremental models from scratch: the FullRefreshModels, or all of them when the list is empty
+	FullRefresh       bool
+	FullRefreshModels []string
+	// Select and Exclude are the selection expressions of --select and --exclude, see ResolveSelection
+	Select  []string
+	Exclude []string
+	// Selected are the executed assets and SkippedTests the tests not executed, set by ResolveSelection
+	Selected     map[string]bool
+	SkippedTests map[string]bool
+}
+
+// IsFullRefresh reports whether the asset is rebuilt from scratch
+func (o *RunOptions) IsFullRefresh(assetName string) bool {
+	if o == nil || !o.FullRefresh {
+		return false
+	}
+	return len(o.FullRefreshModels) == 0 || slices.Contains(o.FullRefreshModels, assetName)
+}
+
+// ValidateFullRefresh checks that the models of the full refresh are assets of the DAG
+func (o *RunOptions) ValidateFullRefresh(assets map[string]Asset) error {
+	if o == nil {
+		return nil
+	}
+	var unknown []string
+	for _, name := range o.FullRefreshModels {
+		if _, ok := assets[name]; !ok {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		return fmt.Errorf("unknown models %s of the full refresh", strings.Join(unknown, ", "))
+	}
+	return nil
+}
+
+// FullRefreshFlag is the --full-refresh flag of the production binary. Without a value it rebuilds
+// all incremental models, --full-refresh=stage.model,stage.model rebuilds the listed ones.
+type FullRefreshFlag struct {
+	Enabled bool
+	Models  []string
+}
+
+// String implements flag.Value.
+func (f *FullRefreshFlag) String() string {
+	if f == nil || !f.Enabled {
+		return "false"
+	}
+	if len(f.Models) == 0 {
+		return "true"
+	}
+	return strings.Join(f.Models, ",")
+}
+
+// Set implements flag.Value.
+func (f *FullRefreshFlag) Set(value string) error {
+	f.Models = nil
+	switch value {
+	case "", "true":
+		f.Enabled = true
+	case "false":
+		f.Enabled = false
+	default:
+		f.Enabled = true
+		for _, name := range strings.Split(value, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				f.Models = append(f.Models, name)
+			}
+		}
+	}
+	return nil
+}
+
+// IsBoolFlag allows --full-refresh without a value.
+func (f *FullRefreshFlag) IsBoolFlag() bool {
+	return true
+}
+
+// ParseRunDate parses a date (2006-01-02, UTC) or a timestamp (RFC 3339), an empty value is the zero time
+func ParseRunDate(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if date, err := time.Parse(RUN_DATE_LAYOUT, value); err == nil {
+		return date, nil
+	}
+	timestamp, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%s is neither a date (2006-01-02) nor a timestamp (RFC 3339)", value)
+	}
+	return timestamp, nil
+}
+
+// Resolve returns the options with the vars of profile.yaml and the defaults of the dates, nil options are the defaults
+func (o *RunOptions) Resolve(now time.Time) (*RunOptions, error) {
+	resolved := &RunOptions{}
+	if o != nil {
+		*resolved = *o
+	}
+	vars, err := ResolveVars(resolved.Vars)
+	if err != nil {
+		return nil, err
+	}
+	resolved.Vars = vars
+
+	if resolved.RunDate.IsZero() {
+		if !resolved.IntervalStart.IsZero() {
+			resolved.RunDate = resolved.IntervalStart
+		} else {
+			year, month, day := now.UTC().Date()
+			resolved.RunDate = time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+		}
+	}
+	if resolved.IntervalStart.IsZero() {
+		resolved.IntervalStart = resolved.RunDate
+	}
+	if resolved.IntervalEnd.IsZero() {
+		resolved.IntervalEnd = resolved.IntervalStart.Add(DEFAULT_INTERVAL)
+	}
+	if !resolved.IntervalEnd.After(resolved.IntervalStart) {
+		return nil, fmt.Errorf("interval end %s must be after interval start %s",
+			resolved.IntervalEnd.Format(time.RFC3339), resolved.IntervalStart.Format(time.RFC3339))
+	}
+	return resolved, nil
+}
+
+// dateFunction formats a date of the task with a Go layout, e.g. {{ RunDate("20060102") }}
+func dateFunction(date time.Time, defaultLayout string) func(layout ...string) string {
+	return func(layout ...string) string {
+		if len(layout) > 0 {
+			return date.Format(layout[0])
+		}
+		return date.Format(defaultLayout)
+	}
+}
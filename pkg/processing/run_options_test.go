@@ -0,0 +1,63 @@
+package processing
+
+import (
+	"flag"
+	"testing"
+	"time"
+
+	pongo2 "github.com/flosch/pongo2/v6"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunOptionsResolveDatesAndTemplateFunctions(t *testing.T) {
+	now := time.Date(2024, 3, 15, 17, 30, 0, 0, time.UTC)
+
+	defaults, err := (*RunOptions)(nil).Resolve(now)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC), defaults.RunDate)
+	assert.Equal(t, defaults.RunDate, defaults.IntervalStart)
+	assert.Equal(t, time.Date(2024, 3, 16, 0, 0, 0, 0, time.UTC), defaults.IntervalEnd)
+
+	start, err := ParseRunDate("2024-01-01")
+	require.NoError(t, err)
+	end, err := ParseRunDate("2024-01-01T06:00:00Z")
+	require.NoError(t, err)
+	options, err := (&RunOptions{IntervalStart: start, IntervalEnd: end}).Resolve(now)
+	require.NoError(t, err)
+	assert.Equal(t, start, options.RunDate, "the run date defaults to the interval start")
+
+	_, err = ParseRunDate("yesterday")
+	assert.Error(t, err)
+	_, err = (&RunOptions{IntervalStart: end, IntervalEnd: start}).Resolve(now)
+	assert.ErrorContains(t, err, "must be after interval start")
+
+	tpl, err := pongo2.FromString(`{{ RunDate() }}|{{ RunDate("20060102") }}|{{ IntervalStart() }}|{{ IntervalEnd("2006-01-02T15:04") }}`)
+	require.NoError(t, err)
+	output, err := tpl.Execute(FromTaskContextPongo2(&TaskContext{
+		Vars:          options.Vars,
+		RunDate:       options.RunDate,
+		IntervalStart: options.IntervalStart,
+		IntervalEnd:   options.IntervalEnd,
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, "2024-01-01|20240101|2024-01-01 00:00:00|2024-01-01T06:00", output)
+}
+
+func TestFullRefreshFlag(t *testing.T) {
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	var fullRefresh FullRefreshFlag
+	flags.Var(&fullRefresh, "full-refresh", "")
+
+	require.NoError(t, flags.Parse([]string{"--full-refresh"}))
+	options := &RunOptions{FullRefresh: fullRefresh.Enabled, FullRefreshModels: fullRefresh.Models}
+	assert.True(t, options.IsFullRefresh("mart.orders"))
+
+	require.NoError(t, flags.Parse([]string{"--full-refresh=mart.orders, mart.events"}))
+	options = &RunOptions{FullRefresh: fullRefresh.Enabled, FullRefreshModels: fullRefresh.Models}
+	assert.True(t, options.IsFullRefresh("mart.events"))
+	assert.False(t, options.IsFullRefresh("mart.customers"))
+	assert.False(t, (*RunOptions)(nil).IsFullRefresh("mart.orders"))
+
+	assert.EqualError(t, options.ValidateFullRefresh(map[string]Asset{"mart.orders": nil}), "unknown models mart.events of the full refresh")
+}
@@ -0,0 +1,201 @@
+package processing
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-teal/teal/pkg/configs"
+	"github.com/go-teal/teal/pkg/drivers"
+	"github.com/rs/zerolog/log"
+)
+
+// schemaDiff is the difference between the columns of a query and of its target table
+type schemaDiff struct {
+	Added   []drivers.ColumnInfo
+	Removed []drivers.ColumnInfo
+	Changed []drivers.ColumnInfo // query columns whose type differs from the table column
+	Current []drivers.ColumnInfo // table columns of Changed, in the same order
+}
+
+func (d schemaDiff) isEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+func (d schemaDiff) String() string {
+	names := func(columns []drivers.ColumnInfo) string {
+		result := make([]string, len(columns))
+		for i, c := range columns {
+			result[i] = c.Name
+		}
+		return strings.Join(result, ", ")
+	}
+	return fmt.Sprintf("added: [%s], removed: [%s], changed type: [%s]", names(d.Added), names(d.Removed), names(d.Changed))
+}
+
+// diffColumns compares column names case-insensitively, as PostgreSQL folds unquoted identifiers
+func diffColumns(queryColumns []drivers.ColumnInfo, tableColumns []drivers.ColumnInfo) schemaDiff {
+	var diff schemaDiff
+	tableColumnsMap := make(map[string]drivers.ColumnInfo, len(tableColumns))
+	for _, c := range tableColumns {
+		tableColumnsMap[strings.ToLower(c.Name)] = c
+	}
+	queryColumnsMap := make(map[string]bool, len(queryColumns))
+	for _, c := range queryColumns {
+		queryColumnsMap[strings.ToLower(c.Name)] = true
+		tableColumn, ok := tableColumnsMap[strings.ToLower(c.Name)]
+		if !ok {
+			diff.Added = append(diff.Added, c)
+		} else if !strings.EqualFold(strings.TrimSpace(tableColumn.Type), strings.TrimSpace(c.Type)) {
+			diff.Changed = append(diff.Changed, c)
+			diff.Current = append(diff.Current, tableColumn)
+		}
+	}
+	for _, c := range tableColumns {
+		if !queryColumnsMap[strings.ToLower(c.Name)] {
+			diff.Removed = append(diff.Removed, c)
+		}
+	}
+	return diff
+}
+
+// schemaChangeStatements returns the ALTER TABLE statements the policy requires for the diff.
+// Column names are quoted as reported by the database, so mixed-case and reserved names are kept.
+func schemaChangeStatements(tableName string, policy configs.OnSchemaChange, diff schemaDiff) ([]string, error) {
+	var statements []string
+	switch policy {
+	case "", configs.ON_SCHEMA_CHANGE_IGNORE:
+		return nil, nil
+	case configs.ON_SCHEMA_CHANGE_FAIL:
+		if !diff.isEmpty() {
+			return nil, fmt.Errorf("schema of %s has changed (%s) and on_schema_change is fail", tableName, diff)
+		}
+	case configs.ON_SCHEMA_CHANGE_APPEND_NEW_COLUMNS:
+		for _, c := range diff.Added {
+			statements = append(statements, fmt.Sprintf("alter table %s add column \"%s\" %s", tableName, c.Name, c.Type))
+		}
+	case configs.ON_SCHEMA_CHANGE_SYNC_ALL_COLUMNS:
+		for _, c := range diff.Added {
+			statements = append(statements, fmt.Sprintf("alter table %s add column \"%s\" %s", tableName, c.Name, c.Type))
+		}
+		for _, c := range diff.Removed {
+			statements = append(statements, fmt.Sprintf("alter table %s drop column \"%s\"", tableName, c.Name))
+		}
+		for _, c := range diff.Changed {
+			statements = append(statements, fmt.Sprintf("alter table %s alter column \"%s\" type %s", tableName, c.Name, c.Type))
+		}
+	default:
+		return nil, fmt.Errorf("unknown on_schema_change value %q", policy)
+	}
+	return statements, nil
+}
+
+// applySchemaChange aligns the existing table with the columns of the model query
+// according to on_schema_change and returns the query columns to insert into
+func (s *SQLModelAsset) applySchemaChange(ctx *TaskContext) ([]string, error) {
+	dbConnection := s.getDBConnection()
+
+	tx, err := dbConnection.Begin()
+	if err != nil {
+		log.Error().Caller().
+			Str("taskId", ctx.TaskID).
+			Str("taskUUID", ctx.TaskUUID).
+			Str("assetName", s.descriptor.Name).
+			Err(err).
+			Msg("Failed to begin transaction")
+		defer dbConnection.Rollback(tx)
+		return nil, err
+	}
+
+	s.functions["IsIncremental"] = func() bool {
+		return true
+	}
+	context := MergePongo2Context(
+		FromConnectionContext(dbConnection, tx, s.descriptor.Name, s.functions),
+		FromTaskContextPongo2(ctx),
+	)
+	sqlQuery, err := renderSQL(s.descriptor.RawSQL, context)
+	if err != nil {
+		defer dbConnection.Rollback(tx)
+		log.Error().Caller().Stack().
+			Str("taskId", ctx.TaskID).
+			Str("taskUUID", ctx.TaskUUID).
+			Str("assetName", s.descriptor.Name).
+			Str("sql", s.descriptor.RawSQL).
+			Err(err).
+			Msg("Failed to render template")
+		return nil, err
+	}
+
+	queryColumns, err := dbConnection.GetQueryColumns(tx, sqlQuery)
+	if err != nil {
+		defer dbConnection.Rollback(tx)
+		log.Error().Caller().
+			Str("taskId", ctx.TaskID).
+			Str("taskUUID", ctx.TaskUUID).
+			Str("assetName", s.descriptor.Name).
+			Err(err).
+			Msg("Failed to get the columns of the query")
+		return nil, err
+	}
+	tableColumns, err := dbConnection.GetTableColumns(tx, s.descriptor.Name)
+	if err != nil {
+		defer dbConnection.Rollback(tx)
+		log.Error().Caller().
+			Str("taskId", ctx.TaskID).
+			Str("taskUUID", ctx.TaskUUID).
+			Str("assetName", s.descriptor.Name).
+			Err(err).
+			Msg("Failed to get the columns of the table")
+		return nil, err
+	}
+
+	diff := diffColumns(queryColumns, tableColumns)
+	statements, err := schemaChangeStatements(s.descriptor.Name, s.descriptor.ModelProfile.OnSchemaChange, diff)
+	if err != nil {
+		defer dbConnection.Rollback(tx)
+		log.Error().Caller().
+			Str("taskId", ctx.TaskID).
+			Str("taskUUID", ctx.TaskUUID).
+			Str("assetName", s.descriptor.Name).
+			Err(err).
+			Msg("Schema change is not allowed")
+		return nil, err
+	}
+	if !diff.isEmpty() {
+		log.Info().
+			Str("taskId", ctx.TaskID).
+			Str("taskUUID", ctx.TaskUUID).
+			Str("assetName", s.descriptor.Name).
+			Strs("statements", statements).
+			Msgf("Schema change detected: %s", diff)
+	}
+	if s.descriptor.ModelProfile.OnSchemaChange == configs.ON_SCHEMA_CHANGE_APPEND_NEW_COLUMNS {
+		for i, c := range diff.Changed {
+			log.Warn().
+				Str("taskId", ctx.TaskID).
+				Str("taskUUID", ctx.TaskUUID).
+				Str("assetName", s.descriptor.Name).
+				Msgf("Type of column %s has changed from %s to %s, append_new_columns keeps %s and the insert fails if the values can not be cast, use sync_all_columns to alter the column",
+					c.Name, diff.Current[i].Type, c.Type, diff.Current[i].Type)
+		}
+	}
+	for _, statement := range statements {
+		if err = dbConnection.Exec(tx, statement); err != nil {
+			defer dbConnection.Rollback(tx)
+			log.Error().Caller().
+				Str("taskId", ctx.TaskID).
+				Str("taskUUID", ctx.TaskUUID).
+				Str("assetName", s.descriptor.Name).
+				Str("sql", statement).
+				Err(err).
+				Msg("Failed to apply schema change")
+			return nil, err
+		}
+	}
+
+	fields := make([]string, len(queryColumns))
+	for i, c := range queryColumns {
+		fields[i] = fmt.Sprintf("\"%s\"", c.Name)
+	}
+	return fields, dbConnection.Commit(tx)
+}
@@ -0,0 +1,74 @@
+package processing
+
+import (
+	"testing"
+
+	"github.com/go-teal/teal/pkg/configs"
+	"github.com/go-teal/teal/pkg/drivers"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffColumns(t *testing.T) {
+	tableColumns := []drivers.ColumnInfo{
+		{Name: "id", Type: "INTEGER"},
+		{Name: "amount", Type: "INTEGER"},
+		{Name: "legacy", Type: "VARCHAR"},
+	}
+	queryColumns := []drivers.ColumnInfo{
+		{Name: "ID", Type: "integer"},
+		{Name: "amount", Type: "DOUBLE"},
+		{Name: "created_at", Type: "TIMESTAMP"},
+	}
+
+	diff := diffColumns(queryColumns, tableColumns)
+
+	assert.Equal(t, []drivers.ColumnInfo{{Name: "created_at", Type: "TIMESTAMP"}}, diff.Added)
+	assert.Equal(t, []drivers.ColumnInfo{{Name: "legacy", Type: "VARCHAR"}}, diff.Removed)
+	assert.Equal(t, []drivers.ColumnInfo{{Name: "amount", Type: "DOUBLE"}}, diff.Changed)
+	assert.Equal(t, []drivers.ColumnInfo{{Name: "amount", Type: "INTEGER"}}, diff.Current)
+	assert.True(t, diffColumns(tableColumns, tableColumns).isEmpty())
+}
+
+func TestSchemaChangeStatements(t *testing.T) {
+	diff := schemaDiff{
+		Added:   []drivers.ColumnInfo{{Name: "created_at", Type: "TIMESTAMP"}},
+		Removed: []drivers.ColumnInfo{{Name: "legacy", Type: "VARCHAR"}},
+		Changed: []drivers.ColumnInfo{{Name: "amount", Type: "DOUBLE"}},
+	}
+	cases := []struct {
+		policy  configs.OnSchemaChange
+		want    []string
+		wantErr bool
+	}{
+		{configs.ON_SCHEMA_CHANGE_IGNORE, nil, false},
+		{"", nil, false},
+		{configs.ON_SCHEMA_CHANGE_FAIL, nil, true},
+		{configs.ON_SCHEMA_CHANGE_APPEND_NEW_COLUMNS, []string{
+			"alter table dds.fact add column \"created_at\" TIMESTAMP",
+		}, false},
+		{configs.ON_SCHEMA_CHANGE_SYNC_ALL_COLUMNS, []string{
+			"alter table dds.fact add column \"created_at\" TIMESTAMP",
+			"alter table dds.fact drop column \"legacy\"",
+			"alter table dds.fact alter column \"amount\" type DOUBLE",
+		}, false},
+		{"drop_everything", nil, true},
+	}
+	for _, c := range cases {
+		statements, err := schemaChangeStatements("dds.fact", c.policy, diff)
+		assert.Equal(t, c.wantErr, err != nil, "policy %q", c.policy)
+		assert.Equal(t, c.want, statements, "policy %q", c.policy)
+	}
+
+	statements, err := schemaChangeStatements("dds.fact", configs.ON_SCHEMA_CHANGE_APPEND_NEW_COLUMNS, schemaDiff{
+		Added: []drivers.ColumnInfo{{Name: "OrderDate", Type: "DATE"}, {Name: "order", Type: "INTEGER"}},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"alter table dds.fact add column \"OrderDate\" DATE",
+		"alter table dds.fact add column \"order\" INTEGER",
+	}, statements)
+
+	statements, err = schemaChangeStatements("dds.fact", configs.ON_SCHEMA_CHANGE_FAIL, schemaDiff{})
+	assert.NoError(t, err)
+	assert.Empty(t, statements)
+}
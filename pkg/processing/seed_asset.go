@@ -0,0 +1,200 @@
+package processing
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/go-teal/teal/pkg/core"
+	"github.com/go-teal/teal/pkg/models"
+	"github.com/rs/zerolog/log"
+)
+
+// SEED_HASH_PREFIX marks the comment teal puts on a seed table to store the hash of its file
+const SEED_HASH_PREFIX = "teal:seed_hash="
+
+type SeedAsset struct {
+	descriptor *models.SeedDescriptor
+}
+
+func InitSeedAsset(descriptor *models.SeedDescriptor) Asset {
+	return &SeedAsset{
+		descriptor: descriptor,
+	}
+}
+
+// GetName implements Asset.
+func (s *SeedAsset) GetName() string {
+	return s.descriptor.Name
+}
+
+// GetDescriptor implements Asset.
+func (s *SeedAsset) GetDescriptor() any {
+	return s.descriptor
+}
+
+// GetDownstreams implements Asset.
+func (s *SeedAsset) GetDownstreams() []string {
+	return s.descriptor.Downstreams
+}
+
+// GetUpstreams implements Asset.
+func (s *SeedAsset) GetUpstreams() []string {
+	return s.descriptor.Upstreams
+}
+
+// Execute implements Asset.
+// The seed table is reloaded only when the hash of the file or of the column types changes.
+func (s *SeedAsset) Execute(ctx *TaskContext) (interface{}, error) {
+	dbConnection := core.GetInstance().GetDBConnection(s.descriptor.ModelProfile.Connection)
+
+	dbConnection.ConcurrencyLock()
+	defer dbConnection.ConcurrencyUnlock()
+
+	log.Debug().
+		Str("taskId", ctx.TaskID).
+		Str("taskUUID", ctx.TaskUUID).
+		Str("assetName", s.descriptor.Name).
+		Str("connection", s.descriptor.ModelProfile.Connection).
+		Str("filePath", s.descriptor.FilePath).
+		Msg("Executing seed")
+
+	seedHash, err := s.hash()
+	if err != nil {
+		log.Error().Caller().
+			Str("taskId", ctx.TaskID).
+			Str("taskUUID", ctx.TaskUUID).
+			Str("assetName", s.descriptor.Name).
+			Str("filePath", s.descriptor.FilePath).
+			Err(err).
+			Msg("Failed to read seed file")
+		return nil, err
+	}
+	comment := SEED_HASH_PREFIX + seedHash
+
+	tx, err := dbConnection.Begin()
+	if err != nil {
+		log.Error().Caller().
+			Str("taskId", ctx.TaskID).
+			Str("taskUUID", ctx.TaskUUID).
+			Str("assetName", s.descriptor.Name).
+			Err(err).
+			Msg("Failed to begin transaction")
+		defer dbConnection.Rollback(tx)
+		return nil, err
+	}
+
+	if !dbConnection.CheckSchemaExists(tx, s.descriptor.Name) {
+		err = dbConnection.CreateSchema(tx, strings.Split(s.descriptor.Name, ".")[0])
+		if err != nil {
+			defer dbConnection.Rollback(tx)
+			log.Error().Caller().
+				Str("taskId", ctx.TaskID).
+				Str("taskUUID", ctx.TaskUUID).
+				Str("assetName", s.descriptor.Name).
+				Err(err).
+				Msg("Failed to create schema")
+			return nil, err
+		}
+	}
+
+	if dbConnection.CheckTableExists(tx, s.descriptor.Name) {
+		existingComment, err := dbConnection.GetRelationComment(tx, s.descriptor.Name)
+		if err == nil && existingComment == comment {
+			err = dbConnection.ApplyGrants(tx, s.descriptor.Name, s.descriptor.ModelProfile.Grants)
+			if err != nil {
+				defer dbConnection.Rollback(tx)
+				log.Error().Caller().
+					Str("taskId", ctx.TaskID).
+					Str("taskUUID", ctx.TaskUUID).
+					Str("assetName", s.descriptor.Name).
+					Err(err).
+					Msg("Failed to apply grants")
+				return nil, err
+			}
+			log.Info().
+				Str("taskId", ctx.TaskID).
+				Str("taskUUID", ctx.TaskUUID).
+				Str("assetName", s.descriptor.Name).
+				Msg("Seed is up to date")
+			return nil, dbConnection.Commit(tx)
+		}
+	}
+
+	err = dbConnection.LoadSeed(tx, s.descriptor.Name, s.descriptor.FilePath, s.descriptor.Format, s.descriptor.ModelProfile.ColumnTypes)
+	if err != nil {
+		defer dbConnection.Rollback(tx)
+		log.Error().Caller().
+			Str("taskId", ctx.TaskID).
+			Str("taskUUID", ctx.TaskUUID).
+			Str("assetName", s.descriptor.Name).
+			Str("filePath", s.descriptor.FilePath).
+			Err(err).
+			Msg("Failed to load seed")
+		return nil, err
+	}
+
+	err = dbConnection.ApplyGrants(tx, s.descriptor.Name, s.descriptor.ModelProfile.Grants)
+	if err != nil {
+		defer dbConnection.Rollback(tx)
+		log.Error().Caller().
+			Str("taskId", ctx.TaskID).
+			Str("taskUUID", ctx.TaskUUID).
+			Str("assetName", s.descriptor.Name).
+			Err(err).
+			Msg("Failed to apply grants")
+		return nil, err
+	}
+
+	err = dbConnection.Exec(tx, fmt.Sprintf("comment on table %s is '%s'", s.descriptor.Name, comment))
+	if err != nil {
+		defer dbConnection.Rollback(tx)
+		log.Error().Caller().
+			Str("taskId", ctx.TaskID).
+			Str("taskUUID", ctx.TaskUUID).
+			Str("assetName", s.descriptor.Name).
+			Err(err).
+			Msg("Failed to store the seed hash")
+		return nil, err
+	}
+
+	log.Info().
+		Str("taskId", ctx.TaskID).
+		Str("taskUUID", ctx.TaskUUID).
+		Str("assetName", s.descriptor.Name).
+		Str("filePath", s.descriptor.FilePath).
+		Msg("Seed loaded")
+	return nil, dbConnection.Commit(tx)
+}
+
+// RunTests implements Asset.
+func (s *SeedAsset) RunTests(ctx *TaskContext, testsMap map[string]ModelTesting) []TestResult {
+	return runModelTests(ctx, s.descriptor.Name, s.descriptor.ModelProfile.Tests, testsMap)
+}
+
+// hash covers the file content and the column type overrides, both change the loaded table
+func (s *SeedAsset) hash() (string, error) {
+	file, err := os.Open(s.descriptor.FilePath)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	columns := make([]string, 0, len(s.descriptor.ModelProfile.ColumnTypes))
+	for column := range s.descriptor.ModelProfile.ColumnTypes {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+	for _, column := range columns {
+		fmt.Fprintf(hash, "\n%s=%s", column, s.descriptor.ModelProfile.ColumnTypes[column])
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -0,0 +1,180 @@
+package processing
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/go-teal/teal/pkg/configs"
+	"github.com/go-teal/teal/pkg/models"
+)
+
+const (
+	SELECTOR_TAG   = "tag:"
+	SELECTOR_STAGE = "stage:"
+)
+
+// selector is a single selection expression: a name, tag:<tag> or stage:<stage>,
+// +selector adds the upstreams of the matched assets and selector+ their downstreams
+type selector struct {
+	value       string
+	upstreams   bool
+	downstreams bool
+}
+
+// SplitSelectors splits the --select and --exclude values, the expressions are separated by commas or spaces
+func SplitSelectors(value string) []string {
+	return strings.FieldsFunc(value, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t' || r == '\n'
+	})
+}
+
+func parseSelector(expression string) (*selector, error) {
+	s := &selector{value: expression}
+	if strings.HasPrefix(s.value, "+") {
+		s.upstreams = true
+		s.value = s.value[1:]
+	}
+	if strings.HasSuffix(s.value, "+") {
+		s.downstreams = true
+		s.value = s.value[:len(s.value)-1]
+	}
+	if s.value == "" || s.value == SELECTOR_TAG || s.value == SELECTOR_STAGE {
+		return nil, fmt.Errorf("invalid selection expression %q", expression)
+	}
+	return s, nil
+}
+
+// isPattern reports whether the selector is a tag or a stage, which may match nothing
+func (s *selector) isPattern() bool {
+	return strings.HasPrefix(s.value, SELECTOR_TAG) || strings.HasPrefix(s.value, SELECTOR_STAGE)
+}
+
+// matches reports whether an asset or a test is matched by the selector before the graph operators
+func (s *selector) matches(name string, tags []string) bool {
+	if tag, ok := strings.CutPrefix(s.value, SELECTOR_TAG); ok {
+		return slices.Contains(tags, tag)
+	}
+	if stage, ok := strings.CutPrefix(s.value, SELECTOR_STAGE); ok {
+		nameStage, _, _ := strings.Cut(name, ".")
+		return nameStage == stage
+	}
+	return name == s.value
+}
+
+// matchAssets returns the assets matched by the selector with their upstreams or downstreams,
+// the graph operators do not apply to tests
+func (s *selector) matchAssets(assets map[string]Asset) map[string]bool {
+	matched := make(map[string]bool)
+	for name, asset := range assets {
+		var tags []string
+		if profile := assetModelProfile(asset); profile != nil {
+			tags = profile.Tags
+		}
+		if s.matches(name, tags) {
+			matched[name] = true
+		}
+	}
+	if s.upstreams {
+		addRelatives(assets, matched, Asset.GetUpstreams)
+	}
+	if s.downstreams {
+		addRelatives(assets, matched, Asset.GetDownstreams)
+	}
+	return matched
+}
+
+// addRelatives adds the transitive upstreams or downstreams to the matched assets
+func addRelatives(assets map[string]Asset, matched map[string]bool, relatives func(Asset) []string) {
+	queue := make([]string, 0, len(matched))
+	for name := range matched {
+		queue = append(queue, name)
+	}
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		for _, relative := range relatives(assets[name]) {
+			if _, ok := assets[relative]; ok && !matched[relative] {
+				matched[relative] = true
+				queue = append(queue, relative)
+			}
+		}
+	}
+}
+
+func testProfile(test ModelTesting) *configs.TestProfile {
+	if descriptor, ok := test.GetDescriptor().(*models.SQLModelTestDescriptor); ok {
+		return descriptor.TestProfile
+	}
+	return nil
+}
+
+// ResolveSelection sets Selected and SkippedTests from the Select and Exclude expressions.
+// Without Select all assets are selected, disabled assets and tests are never executed.
+// Exclude matches assets and tests, the tests of the selected assets run unless excluded.
+func (o *RunOptions) ResolveSelection(assets map[string]Asset, tests map[string]ModelTesting) error {
+	selected := make(map[string]bool, len(assets))
+	if len(o.Select) == 0 {
+		for name := range assets {
+			selected[name] = true
+		}
+	}
+	for _, expression := range o.Select {
+		s, err := parseSelector(expression)
+		if err != nil {
+			return err
+		}
+		matched := s.matchAssets(assets)
+		if !s.isPattern() && len(matched) == 0 {
+			return fmt.Errorf("unknown model %s of --select", s.value)
+		}
+		for name := range matched {
+			selected[name] = true
+		}
+	}
+
+	skippedTests := make(map[string]bool)
+	for name, test := range tests {
+		if profile := testProfile(test); profile != nil && !profile.IsEnabled() {
+			skippedTests[name] = true
+		}
+	}
+	for _, expression := range o.Exclude {
+		s, err := parseSelector(expression)
+		if err != nil {
+			return err
+		}
+		matched := s.matchAssets(assets)
+		for name := range matched {
+			delete(selected, name)
+		}
+		matchedTests := 0
+		for name, test := range tests {
+			var tags []string
+			if profile := testProfile(test); profile != nil {
+				tags = profile.Tags
+			}
+			if s.matches(name, tags) {
+				skippedTests[name] = true
+				matchedTests++
+			}
+		}
+		if !s.isPattern() && len(matched) == 0 && matchedTests == 0 {
+			return fmt.Errorf("unknown model or test %s of --exclude", s.value)
+		}
+	}
+
+	for name := range selected {
+		if profile := assetModelProfile(assets[name]); profile != nil && !profile.IsEnabled() {
+			delete(selected, name)
+		}
+	}
+	o.Selected = selected
+	o.SkippedTests = skippedTests
+	return nil
+}
+
+// IsSelected reports whether the asset is executed by the task, all assets are selected before ResolveSelection
+func (o *RunOptions) IsSelected(assetName string) bool {
+	return o == nil || o.Selected == nil || o.Selected[assetName]
+}
@@ -0,0 +1,68 @@
+package processing
+
+import (
+	"testing"
+
+	"github.com/go-teal/teal/pkg/configs"
+	"github.com/go-teal/teal/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func selectionAsset(name string, upstreams []string, downstreams []string, profile *configs.ModelProfile) Asset {
+	return InitSQLModelAsset(&models.SQLModelDescriptor{
+		Name:         name,
+		Upstreams:    upstreams,
+		Downstreams:  downstreams,
+		ModelProfile: profile,
+	})
+}
+
+func selectionTest(name string, profile *configs.TestProfile) ModelTesting {
+	return InitSQLModelTesting(&models.SQLModelTestDescriptor{Name: name, TestProfile: profile})
+}
+
+func TestResolveSelection(t *testing.T) {
+	disabled := false
+	// staging.orders -> dds.orders -> mart.revenue <- staging.rates, mart.legacy is disabled
+	assets := map[string]Asset{
+		"staging.orders": selectionAsset("staging.orders", nil, []string{"dds.orders"}, &configs.ModelProfile{}),
+		"staging.rates":  selectionAsset("staging.rates", nil, []string{"mart.revenue"}, &configs.ModelProfile{Tags: []string{"daily"}}),
+		"dds.orders":     selectionAsset("dds.orders", []string{"staging.orders"}, []string{"mart.revenue"}, &configs.ModelProfile{}),
+		"mart.revenue":   selectionAsset("mart.revenue", []string{"dds.orders", "staging.rates"}, nil, &configs.ModelProfile{Tags: []string{"daily", "finance"}}),
+		"mart.legacy":    selectionAsset("mart.legacy", nil, nil, &configs.ModelProfile{Tags: []string{"daily"}, Enabled: &disabled}),
+	}
+	tests := map[string]ModelTesting{
+		"dds.test_orders_unique": selectionTest("dds.test_orders_unique", &configs.TestProfile{Tags: []string{"slow"}}),
+		"mart.test_revenue":      selectionTest("mart.test_revenue", &configs.TestProfile{}),
+		"root.test_legacy":       selectionTest("root.test_legacy", &configs.TestProfile{Enabled: &disabled}),
+	}
+	resolve := func(selectExpression string, excludeExpression string) *RunOptions {
+		options := &RunOptions{Select: SplitSelectors(selectExpression), Exclude: SplitSelectors(excludeExpression)}
+		require.NoError(t, options.ResolveSelection(assets, tests))
+		return options
+	}
+
+	options := resolve("", "")
+	assert.Len(t, options.Selected, 4, "all enabled assets are selected by default")
+	assert.False(t, options.IsSelected("mart.legacy"))
+	assert.Equal(t, map[string]bool{"root.test_legacy": true}, options.SkippedTests)
+	assert.True(t, (*RunOptions)(nil).IsSelected("mart.legacy"))
+
+	assert.Equal(t, map[string]bool{"staging.rates": true, "mart.revenue": true}, resolve("tag:daily", "").Selected)
+	assert.Equal(t, map[string]bool{"staging.orders": true, "dds.orders": true, "staging.rates": true, "mart.revenue": true}, resolve("+mart.revenue", "").Selected)
+	assert.Equal(t, map[string]bool{"dds.orders": true, "mart.revenue": true}, resolve("dds.orders+", "").Selected)
+	assert.Equal(t, map[string]bool{"staging.orders": true, "staging.rates": true}, resolve("stage:staging, mart.legacy", "").Selected,
+		"disabled assets are not selected by name")
+	assert.Equal(t, map[string]bool{"staging.orders": true, "dds.orders": true}, resolve("stage:staging stage:dds", "staging.rates").Selected)
+
+	options = resolve("", "tag:slow tag:finance")
+	assert.False(t, options.IsSelected("mart.revenue"))
+	assert.True(t, options.SkippedTests["dds.test_orders_unique"])
+	assert.False(t, options.SkippedTests["mart.test_revenue"])
+
+	assert.EqualError(t, (&RunOptions{Select: []string{"mart.unknown"}}).ResolveSelection(assets, tests), "unknown model mart.unknown of --select")
+	assert.EqualError(t, (&RunOptions{Exclude: []string{"dds.test_unknown"}}).ResolveSelection(assets, tests), "unknown model or test dds.test_unknown of --exclude")
+	assert.EqualError(t, (&RunOptions{Select: []string{"tag:+"}}).ResolveSelection(assets, tests), `invalid selection expression "tag:+"`)
+	assert.NoError(t, (&RunOptions{Select: []string{"tag:unused"}}).ResolveSelection(assets, tests), "tags and stages may match nothing")
+}
@@ -0,0 +1,177 @@
+package processing
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-teal/teal/pkg/configs"
+	"github.com/go-teal/teal/pkg/core"
+	"github.com/go-teal/teal/pkg/models"
+	"github.com/rs/zerolog/log"
+)
+
+// FreshnessStatus is the result of the freshness check of a source table
+type FreshnessStatus string
+
+const (
+	FRESHNESS_PASS  FreshnessStatus = "pass"
+	FRESHNESS_WARN  FreshnessStatus = "warn"
+	FRESHNESS_ERROR FreshnessStatus = "error"
+)
+
+// SourceAsset checks a table loaded outside of the project before its downstreams run.
+// A missing table or a table older than error_after fails the asset, older than warn_after logs a warning.
+type SourceAsset struct {
+	descriptor *models.SourceDescriptor
+}
+
+func InitSourceAsset(descriptor *models.SourceDescriptor) Asset {
+	return &SourceAsset{
+		descriptor: descriptor,
+	}
+}
+
+// GetName implements Asset.
+func (s *SourceAsset) GetName() string {
+	return s.descriptor.Name
+}
+
+// GetDescriptor implements Asset.
+func (s *SourceAsset) GetDescriptor() any {
+	return s.descriptor
+}
+
+// GetDownstreams implements Asset.
+func (s *SourceAsset) GetDownstreams() []string {
+	return s.descriptor.Downstreams
+}
+
+// GetUpstreams implements Asset. Sources are always roots of the DAG.
+func (s *SourceAsset) GetUpstreams() []string {
+	return nil
+}
+
+// Execute implements Asset.
+func (s *SourceAsset) Execute(ctx *TaskContext) (interface{}, error) {
+	sourceProfile := s.descriptor.SourceProfile
+	relationName := sourceProfile.GetRelationName()
+	dbConnection := core.GetInstance().GetDBConnection(sourceProfile.Connection)
+
+	dbConnection.ConcurrencyLock()
+	defer dbConnection.ConcurrencyUnlock()
+
+	log.Debug().
+		Str("taskId", ctx.TaskID).
+		Str("taskUUID", ctx.TaskUUID).
+		Str("assetName", s.descriptor.Name).
+		Str("connection", sourceProfile.Connection).
+		Str("relation", relationName).
+		Msg("Checking source")
+
+	tx, err := dbConnection.Begin()
+	if err != nil {
+		log.Error().Caller().
+			Str("taskId", ctx.TaskID).
+			Str("taskUUID", ctx.TaskUUID).
+			Str("assetName", s.descriptor.Name).
+			Err(err).
+			Msg("Failed to begin transaction")
+		defer dbConnection.Rollback(tx)
+		return nil, err
+	}
+
+	if !dbConnection.CheckTableExists(tx, relationName) {
+		defer dbConnection.Rollback(tx)
+		err = fmt.Errorf("source table %s does not exist", relationName)
+		log.Error().
+			Str("taskId", ctx.TaskID).
+			Str("taskUUID", ctx.TaskUUID).
+			Str("assetName", s.descriptor.Name).
+			Err(err).
+			Msg("Source check failed")
+		return nil, err
+	}
+
+	if sourceProfile.LoadedAtField == "" || sourceProfile.Freshness == nil {
+		log.Info().
+			Str("taskId", ctx.TaskID).
+			Str("taskUUID", ctx.TaskUUID).
+			Str("assetName", s.descriptor.Name).
+			Msg("Source table exists")
+		return nil, dbConnection.Commit(tx)
+	}
+
+	loadedAt, err := dbConnection.GetMaxTimestamp(tx, relationName, sourceProfile.LoadedAtField)
+	if err != nil {
+		defer dbConnection.Rollback(tx)
+		log.Error().Caller().
+			Str("taskId", ctx.TaskID).
+			Str("taskUUID", ctx.TaskUUID).
+			Str("assetName", s.descriptor.Name).
+			Str("loadedAtField", sourceProfile.LoadedAtField).
+			Err(err).
+			Msg("Failed to read the freshness of the source")
+		return nil, err
+	}
+	if err = dbConnection.Commit(tx); err != nil {
+		return nil, err
+	}
+
+	status, message := evaluateFreshness(sourceProfile.Freshness, loadedAt, time.Now())
+	switch status {
+	case FRESHNESS_ERROR:
+		err = fmt.Errorf("source %s is stale: %s", s.descriptor.Name, message)
+		log.Error().
+			Str("taskId", ctx.TaskID).
+			Str("taskUUID", ctx.TaskUUID).
+			Str("assetName", s.descriptor.Name).
+			Err(err).
+			Msg("Source freshness check failed, downstreams are blocked")
+		return nil, err
+	case FRESHNESS_WARN:
+		log.Warn().
+			Str("taskId", ctx.TaskID).
+			Str("taskUUID", ctx.TaskUUID).
+			Str("assetName", s.descriptor.Name).
+			Str("freshness", message).
+			Msg("Source is stale")
+	default:
+		log.Info().
+			Str("taskId", ctx.TaskID).
+			Str("taskUUID", ctx.TaskUUID).
+			Str("assetName", s.descriptor.Name).
+			Str("freshness", message).
+			Msg("Source is fresh")
+	}
+	return nil, nil
+}
+
+// RunTests implements Asset. Sources have no tests, the freshness check is their execution.
+func (s *SourceAsset) RunTests(ctx *TaskContext, testsMap map[string]ModelTesting) []TestResult {
+	return make([]TestResult, 0)
+}
+
+// evaluateFreshness compares the age of the newest row with the thresholds (validated at generation).
+// A table without rows exceeds every threshold.
+func evaluateFreshness(freshness *configs.FreshnessProfile, loadedAt *time.Time, now time.Time) (FreshnessStatus, string) {
+	errorAfter, _ := time.ParseDuration(freshness.ErrorAfter)
+	warnAfter, _ := time.ParseDuration(freshness.WarnAfter)
+	if loadedAt == nil {
+		switch {
+		case freshness.ErrorAfter != "":
+			return FRESHNESS_ERROR, "the table has no rows"
+		case freshness.WarnAfter != "":
+			return FRESHNESS_WARN, "the table has no rows"
+		}
+		return FRESHNESS_PASS, "the table has no rows"
+	}
+	age := now.Sub(*loadedAt).Truncate(time.Second)
+	message := fmt.Sprintf("the newest row is loaded at %s, %s ago", loadedAt.Format(time.RFC3339), age)
+	switch {
+	case freshness.ErrorAfter != "" && age > errorAfter:
+		return FRESHNESS_ERROR, message + ", error_after is " + freshness.ErrorAfter
+	case freshness.WarnAfter != "" && age > warnAfter:
+		return FRESHNESS_WARN, message + ", warn_after is " + freshness.WarnAfter
+	}
+	return FRESHNESS_PASS, message
+}
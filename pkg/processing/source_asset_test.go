@@ -0,0 +1,39 @@
+package processing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-teal/teal/pkg/configs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvaluateFreshness(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) *time.Time {
+		loadedAt := now.Add(-d)
+		return &loadedAt
+	}
+	both := &configs.FreshnessProfile{WarnAfter: "1h", ErrorAfter: "6h"}
+	warnOnly := &configs.FreshnessProfile{WarnAfter: "1h"}
+
+	cases := []struct {
+		name      string
+		freshness *configs.FreshnessProfile
+		loadedAt  *time.Time
+		want      FreshnessStatus
+	}{
+		{"fresh", both, at(30 * time.Minute), FRESHNESS_PASS},
+		{"warn", both, at(2 * time.Hour), FRESHNESS_WARN},
+		{"error", both, at(7 * time.Hour), FRESHNESS_ERROR},
+		{"warn only never errors", warnOnly, at(48 * time.Hour), FRESHNESS_WARN},
+		{"empty table", both, nil, FRESHNESS_ERROR},
+		{"empty table warn only", warnOnly, nil, FRESHNESS_WARN},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			status, message := evaluateFreshness(tc.freshness, tc.loadedAt, now)
+			assert.Equal(t, tc.want, status, message)
+		})
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/go-teal/gota/dataframe"
 	"github.com/go-teal/teal/pkg/configs"
 	"github.com/go-teal/teal/pkg/core"
+	"github.com/go-teal/teal/pkg/drivers"
 	"github.com/go-teal/teal/pkg/models"
 
 	"github.com/rs/zerolog/log"
@@ -17,6 +18,8 @@ import (
 type SQLModelAsset struct {
 	descriptor *models.SQLModelDescriptor
 	functions  pongo2.Context
+	// atomicConnection shares the transaction of an atomic execution, nil otherwise
+	atomicConnection drivers.DBDriver
 }
 
 func InitSQLModelAsset(descriptor *models.SQLModelDescriptor) Asset {
@@ -47,14 +50,78 @@ func (s *SQLModelAsset) GetUpstreams() []string {
 }
 
 // Execute implements Asset.
+// Atomic models execute hooks, materialization and grants in one transaction, rolled back entirely on error.
 func (s *SQLModelAsset) Execute(ctx *TaskContext) (interface{}, error) {
-
-	var data *dataframe.DataFrame
 	dbConnection := core.GetInstance().GetDBConnection(s.descriptor.ModelProfile.Connection)
 
 	dbConnection.ConcurrencyLock()
 	defer dbConnection.ConcurrencyUnlock()
 
+	if !s.descriptor.ModelProfile.Atomic {
+		return s.execute(ctx)
+	}
+
+	var data interface{}
+	err := runAtomic(dbConnection, func(atomicConnection drivers.DBDriver) error {
+		s.atomicConnection = atomicConnection
+		defer func() { s.atomicConnection = nil }()
+		var err error
+		data, err = s.execute(ctx)
+		return err
+	})
+	if err != nil {
+		log.Error().
+			Str("taskId", ctx.TaskID).
+			Str("taskUUID", ctx.TaskUUID).
+			Str("assetName", s.descriptor.Name).
+			Err(err).
+			Msg("Atomic execution failed, all changes are rolled back")
+		return nil, err
+	}
+	return data, nil
+}
+
+func (s *SQLModelAsset) execute(ctx *TaskContext) (interface{}, error) {
+	dbConnection := s.getDBConnection()
+
+	err := executeHooks(ctx, dbConnection, s.descriptor.Name, HOOK_PRE, s.descriptor.ModelProfile.PreHooks, s.functions)
+	if err != nil {
+		return nil, err
+	}
+	data, err := s.materialize(ctx)
+	if err != nil {
+		return nil, &materializationError{err: err}
+	}
+	if err = s.checkNotNullColumns(ctx); err != nil {
+		return nil, err
+	}
+	if s.descriptor.ModelProfile.Materialization != configs.MAT_CUSTOM && s.descriptor.ModelProfile.Materialization != configs.MAT_EXPORT {
+		err = applyGrants(ctx, dbConnection, s.descriptor.Name, s.descriptor.Name, s.descriptor.ModelProfile.Grants)
+		if err != nil {
+			return nil, err
+		}
+	}
+	err = executeHooks(ctx, dbConnection, s.descriptor.Name, HOOK_POST, s.descriptor.ModelProfile.PostHooks, s.functions)
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// getDBConnection returns the connection of the model, sharing the transaction of an atomic execution
+func (s *SQLModelAsset) getDBConnection() drivers.DBDriver {
+	if s.atomicConnection != nil {
+		return s.atomicConnection
+	}
+	return core.GetInstance().GetDBConnection(s.descriptor.ModelProfile.Connection)
+}
+
+// materialize creates or updates the relation of the model
+func (s *SQLModelAsset) materialize(ctx *TaskContext) (interface{}, error) {
+
+	var data *dataframe.DataFrame
+	dbConnection := s.getDBConnection()
+
 	log.Debug().
 		Str("taskId", ctx.TaskID).
 		Str("taskUUID", ctx.TaskUUID).
@@ -67,6 +134,11 @@ func (s *SQLModelAsset) Execute(ctx *TaskContext) (interface{}, error) {
 		Str("assetName", s.descriptor.Name).
 		Msgf("input params: %v", ctx.Input)
 
+	// Exports write files and create no relation
+	if s.descriptor.ModelProfile.Materialization == configs.MAT_EXPORT {
+		return s.export(ctx)
+	}
+
 	tx, err := dbConnection.Begin()
 	if err != nil {
 		log.Error().Caller().
@@ -117,6 +189,61 @@ func (s *SQLModelAsset) Execute(ctx *TaskContext) (interface{}, error) {
 		return nil, err
 	}
 
+	fullRefresh := ctx.FullRefresh && isTableExists && s.descriptor.ModelProfile.Materialization == configs.MAT_INCREMENTAL
+
+	// The contract is checked before anything is written
+	if err = s.checkContract(ctx, isTableExists && s.descriptor.ModelProfile.Materialization == configs.MAT_INCREMENTAL && !fullRefresh); err != nil {
+		return nil, err
+	}
+
+	// A full refresh rebuilds an incremental model as if its table did not exist
+	if fullRefresh {
+		log.Info().
+			Str("taskId", ctx.TaskID).
+			Str("taskUUID", ctx.TaskUUID).
+			Str("assetName", s.descriptor.Name).
+			Msg("Full refresh, the table is dropped and created again")
+		if err = s.execModelSQL(ctx, s.descriptor.DropTableSQL, "drop table for full refresh"); err != nil {
+			return nil, err
+		}
+		isTableExists = false
+	}
+
+	// Partitioned tables (PostgreSQL only) are loaded by the driver in one transaction
+	materialization := s.descriptor.ModelProfile.Materialization
+	if s.descriptor.ModelProfile.PartitionBy != nil && (materialization == configs.MAT_TABLE || materialization == configs.MAT_INCREMENTAL) {
+		if s.descriptor.ModelProfile.PersistInputs {
+			err := s.persistInputs(ctx.Input)
+			if err != nil {
+				log.Error().Caller().
+					Str("taskId", ctx.TaskID).
+					Str("taskUUID", ctx.TaskUUID).
+					Str("assetName", s.descriptor.Name).
+					Err(err).
+					Msg("Failed to persist inputs")
+				return nil, err
+			}
+		}
+		isIncremental := isTableExists && s.descriptor.ModelProfile.Materialization == configs.MAT_INCREMENTAL
+		if s.descriptor.ModelProfile.IsDataFramed {
+			data, err = s.getDataFrame(ctx, isIncremental)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if isIncremental {
+			switch s.descriptor.ModelProfile.OnSchemaChange {
+			case "", configs.ON_SCHEMA_CHANGE_IGNORE:
+			default:
+				if _, err = s.applySchemaChange(ctx); err != nil {
+					return nil, err
+				}
+			}
+		}
+		err = s.materializePartitioned(ctx, isTableExists)
+		return data, err
+	}
+
 	switch s.descriptor.ModelProfile.Materialization {
 	case configs.MAT_INCREMENTAL:
 		if s.descriptor.ModelProfile.PersistInputs {
@@ -141,7 +268,16 @@ func (s *SQLModelAsset) Execute(ctx *TaskContext) (interface{}, error) {
 		if !isTableExists {
 			err = s.createTable(ctx)
 		} else {
-			err = s.insertToTable(ctx)
+			var fields []string
+			switch s.descriptor.ModelProfile.OnSchemaChange {
+			case "", configs.ON_SCHEMA_CHANGE_IGNORE:
+			default:
+				fields, err = s.applySchemaChange(ctx)
+				if err != nil {
+					return nil, err
+				}
+			}
+			err = s.insertToTable(ctx, fields)
 		}
 
 	case configs.MAT_TABLE:
@@ -168,7 +304,31 @@ func (s *SQLModelAsset) Execute(ctx *TaskContext) (interface{}, error) {
 				}
 			}
 			err = s.createTable(ctx)
+		} else if s.descriptor.SwapTableSQL != "" && !s.descriptor.ModelProfile.Atomic {
+			// Atomic models truncate and insert in their transaction, the swap is not needed
+			if s.descriptor.ModelProfile.PersistInputs {
+				err := s.persistInputs(ctx.Input)
+				if err != nil {
+					log.Error().Caller().
+						Err(err).
+						Msg("Failed to persist inputs")
+					return nil, err
+				}
+			}
+			if s.descriptor.ModelProfile.IsDataFramed {
+				log.Warn().
+					Str("taskId", ctx.TaskID).
+					Str("taskUUID", ctx.TaskUUID).
+					Str("assetName", s.descriptor.Name).
+					Msg("Dataframe can slow this operation, considner custom or incremental materialization")
+				data, err = s.getDataFrame(ctx, false)
+				if err != nil {
+					return nil, err
+				}
+			}
+			err = s.rebuildTable(ctx)
 		} else {
+			// Assets generated before build-then-swap: truncate and insert
 			if err = s.truncateTable(ctx); err == nil {
 				log.Debug().
 					Str("taskId", ctx.TaskID).
@@ -195,7 +355,7 @@ func (s *SQLModelAsset) Execute(ctx *TaskContext) (interface{}, error) {
 						return nil, err
 					}
 				}
-				err = s.insertToTable(ctx)
+				err = s.insertToTable(ctx, nil)
 
 				if err != nil {
 					defer dbConnection.Rollback(tx)
@@ -245,7 +405,36 @@ func (s *SQLModelAsset) Execute(ctx *TaskContext) (interface{}, error) {
 		}
 		if !isTableExists {
 			err = s.createView(ctx)
+		} else {
+			err = s.syncView(ctx)
+		}
+	case configs.MAT_MATERIALIZED_VIEW:
+
+		if s.descriptor.ModelProfile.PersistInputs {
+			err := s.persistInputs(ctx.Input)
+			if err != nil {
+				log.Error().Caller().
+					Str("taskId", ctx.TaskID).
+					Str("taskUUID", ctx.TaskUUID).
+					Str("assetName", s.descriptor.Name).
+					Err(err).
+					Msg("Failed to persist inputs")
+				return nil, err
+			}
 		}
+
+		if s.descriptor.ModelProfile.IsDataFramed {
+			log.Warn().
+				Str("taskId", ctx.TaskID).
+				Str("taskUUID", ctx.TaskUUID).
+				Str("assetName", s.descriptor.Name).
+				Msg("Dataframe can slow this operation, considner custom or incremental materialization")
+			data, err = s.getDataFrame(ctx, false)
+			if err != nil {
+				return nil, err
+			}
+		}
+		err = s.syncMaterializedView(ctx, isTableExists)
 	case configs.MAT_CUSTOM:
 
 		if s.descriptor.ModelProfile.PersistInputs {
@@ -278,18 +467,32 @@ func (s *SQLModelAsset) Execute(ctx *TaskContext) (interface{}, error) {
 
 // RunTests implements Asset.
 func (s *SQLModelAsset) RunTests(ctx *TaskContext, testsMap map[string]ModelTesting) []TestResult {
+	return runModelTests(ctx, s.descriptor.Name, s.descriptor.ModelProfile.Tests, testsMap)
+}
+
+// runModelTests executes the tests declared in the profile of an asset
+func runModelTests(ctx *TaskContext, assetName string, tests []*configs.TestProfile, testsMap map[string]ModelTesting) []TestResult {
 	results := make([]TestResult, 0)
 
-	if len(s.descriptor.ModelProfile.Tests) == 0 {
+	if len(tests) == 0 {
 		return results
 	}
 
 	log.Info().
 		Str("taskId", ctx.TaskID).
 		Str("taskUUID", ctx.TaskUUID).
-		Str("assetName", s.descriptor.Name).
-		Msgf("Testing %s", s.descriptor.Name)
-	for _, testConfig := range s.descriptor.ModelProfile.Tests {
+		Str("assetName", assetName).
+		Msgf("Testing %s", assetName)
+	for _, testConfig := range tests {
+		if ctx.SkippedTests[testConfig.Name] {
+			log.Debug().
+				Str("taskId", ctx.TaskID).
+				Str("taskUUID", ctx.TaskUUID).
+				Str("assetName", assetName).
+				Str("testName", testConfig.Name).
+				Msg("Test skipped")
+			continue
+		}
 		startTime := time.Now()
 		result := TestResult{
 			TestName: testConfig.Name,
@@ -299,24 +502,37 @@ func (s *SQLModelAsset) RunTests(ctx *TaskContext, testsMap map[string]ModelTest
 			status, testName, err := testCase.Execute(ctx)
 			result.DurationMs = time.Since(startTime).Milliseconds()
 
-			if status {
+			switch status {
+			case TestStatusSuccess:
 				result.Status = TestStatusSuccess
 				result.Message = testName
 				log.Info().
 					Str("taskId", ctx.TaskID).
 					Str("taskUUID", ctx.TaskUUID).
-					Str("assetName", s.descriptor.Name).
+					Str("assetName", assetName).
 					Str("testName", testName).
 					Int64("durationMs", result.DurationMs).
 					Msg("Success")
-			} else {
+			case TestStatusWarn:
+				result.Status = TestStatusWarn
+				result.Error = err
+				result.Message = testName
+				log.Warn().
+					Str("taskId", ctx.TaskID).
+					Str("taskUUID", ctx.TaskUUID).
+					Str("assetName", assetName).
+					Str("testName", testName).
+					Err(err).
+					Int64("durationMs", result.DurationMs).
+					Msg("Warning")
+			default:
 				result.Status = TestStatusFailed
 				result.Error = err
 				result.Message = testName
 				log.Error().
 					Str("taskId", ctx.TaskID).
 					Str("taskUUID", ctx.TaskUUID).
-					Str("assetName", s.descriptor.Name).
+					Str("assetName", assetName).
 					Str("testName", testName).
 					Err(err).
 					Int64("durationMs", result.DurationMs).
@@ -329,7 +545,7 @@ func (s *SQLModelAsset) RunTests(ctx *TaskContext, testsMap map[string]ModelTest
 			log.Warn().
 				Str("taskId", ctx.TaskID).
 				Str("taskUUID", ctx.TaskUUID).
-				Str("assetName", s.descriptor.Name).
+				Str("assetName", assetName).
 				Msg(result.Message)
 		}
 
@@ -340,7 +556,7 @@ func (s *SQLModelAsset) RunTests(ctx *TaskContext, testsMap map[string]ModelTest
 }
 
 func (s *SQLModelAsset) createView(ctx *TaskContext) error {
-	dbConnection := core.GetInstance().GetDBConnection(s.descriptor.ModelProfile.Connection)
+	dbConnection := s.getDBConnection()
 
 	tx, err := dbConnection.Begin()
 	if err != nil {
@@ -395,6 +611,19 @@ func (s *SQLModelAsset) createView(ctx *TaskContext) error {
 			Msg("Failed to create view")
 		return err
 	}
+	if s.descriptor.ReplaceViewSQL != "" {
+		err = dbConnection.Exec(tx, commentOnViewSQL(s.descriptor.Name, viewFingerprintComment(s.descriptor.CreateViewSQL)))
+		if err != nil {
+			defer dbConnection.Rollback(tx)
+			log.Error().Caller().Stack().
+				Str("taskId", ctx.TaskID).
+				Str("taskUUID", ctx.TaskUUID).
+				Str("assetName", s.descriptor.Name).
+				Err(err).
+				Msg("Failed to store the view fingerprint")
+			return err
+		}
+	}
 	log.Debug().
 		Str("taskId", ctx.TaskID).
 		Str("taskUUID", ctx.TaskUUID).
@@ -404,9 +633,79 @@ func (s *SQLModelAsset) createView(ctx *TaskContext) error {
 	return dbConnection.Commit(tx)
 }
 
+// execModelSQL renders a generated DDL statement of the model and executes it in its own transaction
+func (s *SQLModelAsset) execModelSQL(ctx *TaskContext, sqlTemplate string, action string) error {
+	dbConnection := s.getDBConnection()
+
+	tx, err := dbConnection.Begin()
+	if err != nil {
+		log.Error().Caller().
+			Str("taskId", ctx.TaskID).
+			Str("taskUUID", ctx.TaskUUID).
+			Str("assetName", s.descriptor.Name).
+			Err(err).
+			Msg("Failed to begin transaction")
+		defer dbConnection.Rollback(tx)
+		return err
+	}
+
+	s.functions["IsIncremental"] = func() bool {
+		return false
+	}
+
+	sqlTempl, err := pongo2.FromString(sqlTemplate)
+	if err != nil {
+		defer dbConnection.Rollback(tx)
+		log.Error().Caller().Stack().
+			Str("taskId", ctx.TaskID).
+			Str("taskUUID", ctx.TaskUUID).
+			Str("assetName", s.descriptor.Name).
+			Str("sql", sqlTemplate).
+			Err(err).
+			Msgf("Failed to parse %s SQL template", action)
+		return err
+	}
+
+	context := MergePongo2Context(
+		FromConnectionContext(dbConnection, tx, s.descriptor.Name, s.functions),
+		FromTaskContextPongo2(ctx),
+	)
+	sqlQuery, err := sqlTempl.Execute(context)
+	if err != nil {
+		defer dbConnection.Rollback(tx)
+		log.Error().Caller().Stack().
+			Str("taskId", ctx.TaskID).
+			Str("taskUUID", ctx.TaskUUID).
+			Str("assetName", s.descriptor.Name).
+			Str("sql", sqlQuery).
+			Err(err).
+			Msgf("Failed to render %s SQL template", action)
+		return err
+	}
+	err = dbConnection.Exec(tx, sqlQuery)
+	if err != nil {
+		defer dbConnection.Rollback(tx)
+		log.Error().Caller().Stack().
+			Str("taskId", ctx.TaskID).
+			Str("taskUUID", ctx.TaskUUID).
+			Str("assetName", s.descriptor.Name).
+			Str("sql", sqlQuery).
+			Err(err).
+			Msgf("Failed to %s", action)
+		return err
+	}
+	log.Debug().
+		Str("taskId", ctx.TaskID).
+		Str("taskUUID", ctx.TaskUUID).
+		Str("assetName", s.descriptor.Name).
+		Str("sql", sqlQuery).
+		Msgf("%s executed successfully", action)
+	return dbConnection.Commit(tx)
+}
+
 func (s *SQLModelAsset) createTable(ctx *TaskContext) error {
 
-	dbConnection := core.GetInstance().GetDBConnection(s.descriptor.ModelProfile.Connection)
+	dbConnection := s.getDBConnection()
 
 	tx, err := dbConnection.Begin()
 	if err != nil {
@@ -474,9 +773,61 @@ func (s *SQLModelAsset) createTable(ctx *TaskContext) error {
 	return dbConnection.Commit(tx)
 }
 
+// rebuildTable builds the new data in a shadow table and swaps it with the existing table,
+// so readers never see an empty table and a failed build keeps the old data.
+// If the swap is impossible (e.g. PostgreSQL views depend on the table), the data is
+// copied from the shadow table in a single transaction instead.
+func (s *SQLModelAsset) rebuildTable(ctx *TaskContext) error {
+	err := s.execModelSQL(ctx, s.descriptor.DropShadowTableSQL, "drop shadow table")
+	if err != nil {
+		return err
+	}
+	err = s.execModelSQL(ctx, s.descriptor.CreateShadowTableSQL, "create shadow table")
+	if err != nil {
+		s.dropShadowTable(ctx)
+		return err
+	}
+	// Grants are carried over by the swap, so readers never see the table without them
+	err = applyGrants(ctx, s.getDBConnection(), s.descriptor.Name, s.descriptor.Name+"__shadow", s.descriptor.ModelProfile.Grants)
+	if err != nil {
+		s.dropShadowTable(ctx)
+		return err
+	}
+	err = s.execModelSQL(ctx, s.descriptor.SwapTableSQL, "swap shadow table")
+	if err == nil {
+		return nil
+	}
+	log.Warn().
+		Str("taskId", ctx.TaskID).
+		Str("taskUUID", ctx.TaskUUID).
+		Str("assetName", s.descriptor.Name).
+		Err(err).
+		Msg("Can not swap the shadow table, replacing the data in a single transaction")
+	// The columns of the existing table are listed on both sides, the shadow table may order them differently
+	err = s.execModelSQL(ctx, s.descriptor.ReplaceFromShadowSQL, "replace data from shadow table")
+	if err != nil {
+		s.dropShadowTable(ctx)
+	}
+	return err
+}
+
+// dropShadowTable removes the shadow table after a failed rebuild. A failure is logged only:
+// the error of the rebuild is returned, and the next rebuild drops the shadow table first.
+func (s *SQLModelAsset) dropShadowTable(ctx *TaskContext) {
+	if err := s.execModelSQL(ctx, s.descriptor.DropShadowTableSQL, "drop shadow table"); err != nil {
+		log.Warn().
+			Str("taskId", ctx.TaskID).
+			Str("taskUUID", ctx.TaskUUID).
+			Str("assetName", s.descriptor.Name).
+			Str("shadowTable", s.descriptor.Name+"__shadow").
+			Err(err).
+			Msg("Failed to drop the shadow table, it is dropped by the next rebuild")
+	}
+}
+
 func (s *SQLModelAsset) truncateTable(ctx *TaskContext) error {
 
-	dbConnection := core.GetInstance().GetDBConnection(s.descriptor.ModelProfile.Connection)
+	dbConnection := s.getDBConnection()
 
 	tx, err := dbConnection.Begin()
 	if err != nil {
@@ -516,7 +867,7 @@ func (s *SQLModelAsset) customQuery(ctx *TaskContext) error {
 		return s.descriptor.ModelProfile.Materialization == configs.MAT_INCREMENTAL
 	}
 
-	dbConnection := core.GetInstance().GetDBConnection(s.descriptor.ModelProfile.Connection)
+	dbConnection := s.getDBConnection()
 
 	tx, err := dbConnection.Begin()
 	if err != nil {
@@ -577,8 +928,10 @@ func (s *SQLModelAsset) customQuery(ctx *TaskContext) error {
 	return dbConnection.Commit(tx)
 }
 
-func (s *SQLModelAsset) insertToTable(ctx *TaskContext) error {
-	dbConnection := core.GetInstance().GetDBConnection(s.descriptor.ModelProfile.Connection)
+// insertToTable inserts the query result into the existing table. fields overrides
+// ModelFields (the columns of the table) when the query columns are known.
+func (s *SQLModelAsset) insertToTable(ctx *TaskContext, fields []string) error {
+	dbConnection := s.getDBConnection()
 
 	tx, err := dbConnection.Begin()
 	if err != nil {
@@ -613,6 +966,11 @@ func (s *SQLModelAsset) insertToTable(ctx *TaskContext) error {
 		FromConnectionContext(dbConnection, tx, s.descriptor.Name, s.functions),
 		FromTaskContextPongo2(ctx),
 	)
+	if len(fields) > 0 {
+		context["ModelFields"] = func() string {
+			return strings.Join(fields, ", ")
+		}
+	}
 	sqlQuery, err := runSQLTemplate.Execute(context)
 	if err != nil {
 		defer dbConnection.Rollback(tx)
@@ -652,7 +1010,7 @@ func (s *SQLModelAsset) getDataFrame(ctx *TaskContext, isIncremental bool) (*dat
 		return isIncremental
 	}
 
-	dbConnection := core.GetInstance().GetDBConnection(s.descriptor.ModelProfile.Connection)
+	dbConnection := s.getDBConnection()
 	simleSQLQueryTemplate, err := pongo2.FromString(s.descriptor.RawSQL)
 	if err != nil {
 		log.Error().Caller().Stack().
@@ -695,7 +1053,7 @@ func (s *SQLModelAsset) getDataFrame(ctx *TaskContext, isIncremental bool) (*dat
 }
 
 func (s *SQLModelAsset) persistInputs(inputs map[string]interface{}) error {
-	dbConnection := core.GetInstance().GetDBConnection(s.descriptor.ModelProfile.Connection)
+	dbConnection := s.getDBConnection()
 
 	tx, err := dbConnection.Begin()
 	if err != nil {
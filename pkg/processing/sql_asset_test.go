@@ -0,0 +1,196 @@
+package processing
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/go-teal/gota/dataframe"
+	"github.com/go-teal/teal/pkg/configs"
+	"github.com/go-teal/teal/pkg/core"
+	"github.com/go-teal/teal/pkg/drivers"
+	"github.com/go-teal/teal/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// recordingDriver records the executed statements. Statements containing a key of failures fail with its error.
+type recordingDriver struct {
+	drivers.DBDriver
+	statements []string
+	tables     map[string]bool
+	fields     map[string][]string
+	comments   map[string]string
+	failures   map[string]error
+	commits    int
+	rollbacks  int
+}
+
+// useRecordingDriver registers a recording driver as the connection of the tests
+func useRecordingDriver(connection string) *recordingDriver {
+	driver := &recordingDriver{
+		tables:   make(map[string]bool),
+		fields:   make(map[string][]string),
+		comments: make(map[string]string),
+		failures: make(map[string]error),
+	}
+	core.GetInstance().SetDBConnection(connection, driver)
+	return driver
+}
+
+func (r *recordingDriver) Begin() (interface{}, error) {
+	return "tx", nil
+}
+
+func (r *recordingDriver) Commit(tx interface{}) error {
+	r.commits++
+	return nil
+}
+
+func (r *recordingDriver) Rollback(tx interface{}) error {
+	r.rollbacks++
+	return nil
+}
+
+func (r *recordingDriver) Exec(tx interface{}, sql string) error {
+	sql = strings.TrimSpace(sql)
+	r.statements = append(r.statements, sql)
+	for fragment, err := range r.failures {
+		if strings.Contains(sql, fragment) {
+			return fmt.Errorf("%s: %w", sql, err)
+		}
+	}
+	return nil
+}
+
+func (r *recordingDriver) PersistDataFrame(tx interface{}, name string, df *dataframe.DataFrame) error {
+	r.statements = append(r.statements, "persist "+name)
+	return nil
+}
+
+func (r *recordingDriver) CheckTableExists(tx interface{}, tableName string) bool {
+	return r.tables[tableName]
+}
+
+func (r *recordingDriver) GetListOfFields(tx interface{}, tableName string) []string {
+	return r.fields[tableName]
+}
+
+func (r *recordingDriver) CheckSchemaExists(tx interface{}, schemaName string) bool {
+	return true
+}
+
+func (r *recordingDriver) GetRelationComment(tx interface{}, relationName string) (string, error) {
+	return r.comments[relationName], nil
+}
+
+func (r *recordingDriver) ApplyGrants(tx interface{}, relationName string, grants map[string][]string) error {
+	r.statements = append(r.statements, "grant "+relationName)
+	return nil
+}
+
+func (r *recordingDriver) IsRetryableError(err error) bool {
+	return false
+}
+
+func (r *recordingDriver) ConcurrencyLock() {}
+
+func (r *recordingDriver) ConcurrencyUnlock() {}
+
+func shadowTableAsset(connection string) *SQLModelAsset {
+	return InitSQLModelAsset(&models.SQLModelDescriptor{
+		Name:                 "dds.orders",
+		CreateShadowTableSQL: "create table dds.orders__shadow as (select name, id from staging.orders)",
+		SwapTableSQL:         "drop table dds.orders;\nalter table dds.orders__shadow rename to orders;",
+		ReplaceFromShadowSQL: "delete from dds.orders where true;\ninsert into dds.orders ({{ ModelFields }}) (select {{ ModelFields }} from dds.orders__shadow);\ndrop table dds.orders__shadow;",
+		DropShadowTableSQL:   "drop table if exists dds.orders__shadow",
+		ModelProfile:         &configs.ModelProfile{Connection: connection, Materialization: configs.MAT_TABLE},
+	}).(*SQLModelAsset)
+}
+
+func TestRebuildTableSwapsShadowTable(t *testing.T) {
+	driver := useRecordingDriver("rebuild_swap")
+	asset := shadowTableAsset("rebuild_swap")
+
+	assert.NoError(t, asset.rebuildTable(&TaskContext{}))
+	assert.Equal(t, []string{
+		"drop table if exists dds.orders__shadow",
+		"create table dds.orders__shadow as (select name, id from staging.orders)",
+		"drop table dds.orders;\nalter table dds.orders__shadow rename to orders;",
+	}, driver.statements)
+}
+
+func TestRebuildTableReplacesDataWhenSwapFails(t *testing.T) {
+	driver := useRecordingDriver("rebuild_fallback")
+	asset := shadowTableAsset("rebuild_fallback")
+	driver.fields["dds.orders"] = []string{"id", "name"}
+	driver.failures["alter table"] = errors.New("cannot drop table orders because other objects depend on it")
+
+	assert.NoError(t, asset.rebuildTable(&TaskContext{}))
+	assert.Equal(t, []string{
+		"drop table if exists dds.orders__shadow",
+		"create table dds.orders__shadow as (select name, id from staging.orders)",
+		"drop table dds.orders;\nalter table dds.orders__shadow rename to orders;",
+		"delete from dds.orders where true;\ninsert into dds.orders (id, name) (select id, name from dds.orders__shadow);\ndrop table dds.orders__shadow;",
+	}, driver.statements)
+
+	driver.statements = nil
+	driver.failures["insert into"] = errors.New("column name does not exist")
+	assert.Error(t, asset.rebuildTable(&TaskContext{}))
+	assert.Equal(t, "drop table if exists dds.orders__shadow", driver.statements[len(driver.statements)-1])
+}
+
+func hookedAsset(connection string, atomic bool) *SQLModelAsset {
+	return InitSQLModelAsset(&models.SQLModelDescriptor{
+		Name:           "dds.orders",
+		CreateTableSQL: "create table dds.orders as (select id from staging.orders)",
+		ModelProfile: &configs.ModelProfile{
+			Connection:      connection,
+			Materialization: configs.MAT_TABLE,
+			Atomic:          atomic,
+			PreHooks:        []string{"insert into audit values ('start')", "analyze staging.orders"},
+			PostHooks:       []string{"insert into audit values ('end')", "analyze dds.orders"},
+			Grants:          map[string][]string{"select": {"reporting"}},
+		},
+	}).(*SQLModelAsset)
+}
+
+func TestHooksRunAroundMaterializationAndGrants(t *testing.T) {
+	driver := useRecordingDriver("hooks_order")
+
+	_, err := hookedAsset("hooks_order", false).Execute(&TaskContext{})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"insert into audit values ('start')",
+		"analyze staging.orders",
+		"create table dds.orders as (select id from staging.orders)",
+		"grant dds.orders",
+		"insert into audit values ('end')",
+		"analyze dds.orders",
+	}, driver.statements)
+}
+
+func TestFailedPreHookStopsExecution(t *testing.T) {
+	driver := useRecordingDriver("hooks_pre_failure")
+	driver.failures["analyze staging.orders"] = errors.New("relation staging.orders does not exist")
+
+	_, err := hookedAsset("hooks_pre_failure", false).Execute(&TaskContext{})
+	assert.ErrorContains(t, err, "pre_hook #2")
+	assert.Equal(t, []string{
+		"insert into audit values ('start')",
+		"analyze staging.orders",
+	}, driver.statements)
+}
+
+func TestFailedPostHookRollsBackAtomicExecution(t *testing.T) {
+	driver := useRecordingDriver("hooks_post_failure")
+	driver.failures["analyze dds.orders"] = errors.New("permission denied for table orders")
+
+	_, err := hookedAsset("hooks_post_failure", true).Execute(&TaskContext{})
+	assert.ErrorIs(t, err, ErrAtomicRollback)
+	assert.ErrorContains(t, err, "post_hook #2")
+	assert.Equal(t, "analyze dds.orders", driver.statements[len(driver.statements)-1])
+	assert.Contains(t, driver.statements, "create table dds.orders as (select id from staging.orders)")
+	assert.Equal(t, 0, driver.commits)
+	assert.Equal(t, 1, driver.rollbacks)
+}
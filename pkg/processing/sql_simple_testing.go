@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	pongo2 "github.com/flosch/pongo2/v6"
+	"github.com/go-teal/teal/pkg/configs"
 	"github.com/go-teal/teal/pkg/core"
 	"github.com/go-teal/teal/pkg/models"
 	"github.com/rs/zerolog/log"
@@ -21,14 +22,14 @@ func InitSQLModelTesting(descriptor *models.SQLModelTestDescriptor) ModelTesting
 	}
 }
 
-func (mt *SQLModelTestCase) Execute(ctx *TaskContext) (bool, string, error) {
+func (mt *SQLModelTestCase) Execute(ctx *TaskContext) (TestStatus, string, error) {
 
 	dbConnection := core.GetInstance().GetDBConnection(mt.descriptor.TestProfile.Connection)
 
 	sqlTestTemplate, err := pongo2.FromString(mt.descriptor.CountTestSQL)
 	if err != nil {
 		log.Error().Caller().Stack().Str("taskId", ctx.TaskID).Str("taskUUID", ctx.TaskUUID).Err(err).Str("sql", mt.descriptor.CountTestSQL).Msg("Failed to parse test SQL template")
-		return false, mt.descriptor.Name, err
+		return TestStatusFailed, mt.descriptor.Name, err
 	}
 
 	context := MergePongo2Context(
@@ -39,20 +40,56 @@ func (mt *SQLModelTestCase) Execute(ctx *TaskContext) (bool, string, error) {
 
 	if err != nil {
 		log.Error().Caller().Stack().Str("taskId", ctx.TaskID).Str("taskUUID", ctx.TaskUUID).Err(err).Str("sql", mt.descriptor.CountTestSQL).Msg("Failed to execute test SQL template")
-		return false, mt.descriptor.Name, err
+		return TestStatusFailed, mt.descriptor.Name, err
 	}
 
-	msg, err := dbConnection.SimpleTest(sqlQuery)
+	failures, err := dbConnection.SimpleTest(sqlQuery)
 
 	if err != nil {
-		return false, mt.descriptor.Name, err
+		return TestStatusFailed, mt.descriptor.Name, err
 	}
 
-	if msg != "" {
-		return false, mt.descriptor.Name, fmt.Errorf("count test failed: %s", msg)
+	status, err := EvaluateTestSeverity(mt.descriptor.TestProfile, failures)
+	return status, mt.descriptor.Name, err
+}
+
+// EvaluateTestSeverity returns the status of a test by the number of failing rows:
+// FAILED if error_if holds and the severity is error, WARN if warn_if holds, SUCCESS otherwise
+func EvaluateTestSeverity(profile *configs.TestProfile, failures int64) (TestStatus, error) {
+	if profile == nil {
+		profile = &configs.TestProfile{}
+	}
+	if profile.Severity != configs.TEST_SEVERITY_WARN {
+		failed, err := configs.EvaluateTestCondition(profile.ErrorIf, failures)
+		if err != nil {
+			return TestStatusFailed, err
+		}
+		if failed {
+			return TestStatusFailed, fmt.Errorf("count test failed: %d rows", failures)
+		}
+	}
+	warned, err := configs.EvaluateTestCondition(profile.WarnIf, failures)
+	if err != nil {
+		return TestStatusFailed, err
 	}
+	if warned {
+		return TestStatusWarn, fmt.Errorf("count test warned: %d rows", failures)
+	}
+	return TestStatusSuccess, nil
+}
 
-	return true, mt.descriptor.Name, nil
+// BlocksDownstream reports whether the test results stop the downstreams of the asset:
+// a test failed and block_downstream_on_test_failure of the model is not false
+func BlocksDownstream(asset Asset, results []TestResult) bool {
+	if profile := assetModelProfile(asset); profile != nil && !profile.BlocksDownstreamOnTestFailure() {
+		return false
+	}
+	for _, result := range results {
+		if result.Status == TestStatusFailed {
+			return true
+		}
+	}
+	return false
 }
 
 // GetDescriptor implements ModelTesting.
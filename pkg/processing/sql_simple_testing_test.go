@@ -0,0 +1,57 @@
+package processing
+
+import (
+	"testing"
+
+	"github.com/go-teal/teal/pkg/configs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+)
+
+func TestEvaluateTestSeverity(t *testing.T) {
+	var profiles []*configs.TestProfile
+	require.NoError(t, yaml.Unmarshal([]byte(`
+- name: dds.test_default
+- name: dds.test_warn
+  severity: warn
+- name: dds.test_thresholds
+  warn_if: '> 0'
+  error_if: '>10'
+`), &profiles))
+	for _, profile := range profiles {
+		require.NoError(t, profile.ValidateSeverity())
+	}
+	status := func(profile *configs.TestProfile, failures int64) TestStatus {
+		result, _ := EvaluateTestSeverity(profile, failures)
+		return result
+	}
+
+	assert.Equal(t, TestStatusSuccess, status(profiles[0], 0))
+	assert.Equal(t, TestStatusFailed, status(profiles[0], 1))
+	assert.Equal(t, TestStatusFailed, status(nil, 1), "tests without a profile fail on any row")
+	assert.Equal(t, TestStatusSuccess, status(profiles[1], 0))
+	assert.Equal(t, TestStatusWarn, status(profiles[1], 1000))
+	assert.Equal(t, TestStatusSuccess, status(profiles[2], 0))
+	assert.Equal(t, TestStatusWarn, status(profiles[2], 10))
+	assert.Equal(t, TestStatusFailed, status(profiles[2], 11))
+	_, err := EvaluateTestSeverity(profiles[2], 11)
+	assert.EqualError(t, err, "count test failed: 11 rows")
+
+	assert.EqualError(t, (&configs.TestProfile{Severity: "info"}).ValidateSeverity(), `severity must be error or warn, got "info"`)
+	assert.EqualError(t, (&configs.TestProfile{WarnIf: "~ 10"}).ValidateSeverity(),
+		`warn_if: invalid condition "~ 10", the operator must be one of >, >=, <, <=, =, ==, !=, <>`)
+	assert.EqualError(t, (&configs.TestProfile{ErrorIf: "> ten"}).ValidateSeverity(),
+		`error_if: invalid condition "> ten", expected an operator and a number, e.g. > 10`)
+}
+
+func TestBlocksDownstream(t *testing.T) {
+	nonBlocking := false
+	blocking := selectionAsset("dds.orders", nil, nil, &configs.ModelProfile{})
+	failed := []TestResult{{TestName: "dds.test_orders", Status: TestStatusFailed}}
+	warned := []TestResult{{TestName: "dds.test_orders", Status: TestStatusWarn}, {TestName: "dds.test_missing", Status: TestStatusNotFound}}
+
+	assert.True(t, BlocksDownstream(blocking, failed))
+	assert.False(t, BlocksDownstream(blocking, warned), "warnings never block")
+	assert.False(t, BlocksDownstream(selectionAsset("dds.orders", nil, nil, &configs.ModelProfile{BlockDownstreamOnTestFailure: &nonBlocking}), failed))
+}
@@ -0,0 +1,333 @@
+package processing
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	pongo2 "github.com/flosch/pongo2/v6"
+	"github.com/go-teal/teal/pkg/configs"
+	"github.com/go-teal/teal/pkg/drivers"
+	"github.com/go-teal/teal/pkg/models"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// UNIT_TEST_ACTUAL_TABLE and UNIT_TEST_EXPECTED_TABLE hold the rows of the model and the expected rows of a unit test
+	UNIT_TEST_ACTUAL_TABLE   = "teal_actual"
+	UNIT_TEST_EXPECTED_TABLE = "teal_expected"
+)
+
+// UnitTestResult is the result of a unit test, Missing are the expected rows the model did not return
+// and Unexpected the rows it returned in excess, both with the values of Columns
+type UnitTestResult struct {
+	TestName   string
+	ModelName  string
+	Status     TestStatus
+	Error      string
+	Columns    []string
+	Missing    [][]string
+	Unexpected [][]string
+	DurationMs int64
+}
+
+// Diff renders the differing rows: "-" for the missing rows and "+" for the unexpected ones
+func (r *UnitTestResult) Diff() string {
+	if len(r.Missing) == 0 && len(r.Unexpected) == 0 {
+		return ""
+	}
+	var builder strings.Builder
+	builder.WriteString("  " + strings.Join(r.Columns, " | ") + "\n")
+	for _, row := range r.Missing {
+		builder.WriteString("- " + strings.Join(row, " | ") + "\n")
+	}
+	for _, row := range r.Unexpected {
+		builder.WriteString("+ " + strings.Join(row, " | ") + "\n")
+	}
+	return builder.String()
+}
+
+// RunUnitTests runs the unit tests, each in a fresh in-memory DuckDB created from the first duckdb connection of config.yaml.
+// The selectors are names of unit tests or of models, all unit tests run without selectors.
+func RunUnitTests(
+	config *configs.Config,
+	assets map[string]Asset,
+	unitTests map[string]*models.UnitTestDescriptor,
+	selectors []string,
+) ([]*UnitTestResult, error) {
+	var connection *configs.DBConnectionConfig
+	for _, candidate := range config.Connections {
+		if candidate.Type == "duckdb" {
+			connection = candidate
+			break
+		}
+	}
+	if connection == nil {
+		return nil, fmt.Errorf("unit tests run on DuckDB, config.yaml has no duckdb connection")
+	}
+
+	names, err := SelectUnitTests(unitTests, selectors)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*UnitTestResult, 0, len(names))
+	for _, name := range names {
+		unitTest := unitTests[name]
+		asset, ok := assets[unitTest.ModelName]
+		if !ok {
+			return nil, fmt.Errorf("model %s of unit test %s not found", unitTest.ModelName, name)
+		}
+		// An in-memory copy of the connection, the extensions are loaded as usual
+		inMemory := *connection
+		inMemoryConfig := *connection.Config
+		inMemoryConfig.Path = ""
+		inMemoryConfig.PathEnv = ""
+		inMemory.Config = &inMemoryConfig
+		dbConnection, err := drivers.EstablishDBConnection(&inMemory)
+		if err != nil {
+			return nil, err
+		}
+		if err = dbConnection.Connect(); err != nil {
+			return nil, err
+		}
+		results = append(results, RunUnitTest(dbConnection, asset, unitTest))
+		dbConnection.Close()
+	}
+	return results, nil
+}
+
+// SelectUnitTests returns the sorted names of the unit tests matching the selectors by the name of the test or of its model
+func SelectUnitTests(unitTests map[string]*models.UnitTestDescriptor, selectors []string) ([]string, error) {
+	var names []string
+	matched := make(map[string]bool, len(selectors))
+	for name, unitTest := range unitTests {
+		if len(selectors) == 0 {
+			names = append(names, name)
+			continue
+		}
+		selected := false
+		for _, selector := range selectors {
+			if selector == name || selector == unitTest.ModelName {
+				matched[selector] = true
+				selected = true
+			}
+		}
+		if selected {
+			names = append(names, name)
+		}
+	}
+	var unknown []string
+	for _, selector := range selectors {
+		if !matched[selector] {
+			unknown = append(unknown, selector)
+		}
+	}
+	if len(unknown) > 0 {
+		return nil, fmt.Errorf("no unit tests of %s", strings.Join(unknown, ", "))
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// RunUnitTest creates the fixtures in the database, runs the model and compares its rows with the expected rows
+func RunUnitTest(dbConnection drivers.DBDriver, asset Asset, unitTest *models.UnitTestDescriptor) *UnitTestResult {
+	start := time.Now()
+	result := &UnitTestResult{
+		TestName:  unitTest.Name,
+		ModelName: unitTest.ModelName,
+		Columns:   unitTest.ExpectedColumns,
+		Status:    TestStatusSuccess,
+	}
+	if err := runUnitTest(dbConnection, asset, unitTest, result); err != nil {
+		result.Status = TestStatusFailed
+		result.Error = err.Error()
+	} else if len(result.Missing) > 0 || len(result.Unexpected) > 0 {
+		result.Status = TestStatusFailed
+		result.Error = fmt.Sprintf("%d expected rows missing, %d unexpected rows", len(result.Missing), len(result.Unexpected))
+	}
+	result.DurationMs = time.Since(start).Milliseconds()
+	log.Debug().
+		Str("testName", result.TestName).
+		Str("modelName", result.ModelName).
+		Str("status", string(result.Status)).
+		Int64("durationMs", result.DurationMs).
+		Msg("Unit test executed")
+	return result
+}
+
+func runUnitTest(dbConnection drivers.DBDriver, asset Asset, unitTest *models.UnitTestDescriptor, result *UnitTestResult) error {
+	sqlAsset, ok := asset.(*SQLModelAsset)
+	if !ok {
+		return fmt.Errorf("model %s is not a SQL model", unitTest.ModelName)
+	}
+	db, ok := dbConnection.GetRawConnection().(*sql.DB)
+	if !ok {
+		return fmt.Errorf("unit tests require a database/sql connection")
+	}
+
+	modelSQL, err := renderUnitTestModel(dbConnection, sqlAsset, unitTest)
+	if err != nil {
+		return err
+	}
+
+	var statements []string
+	for _, fixture := range unitTest.Fixtures {
+		if schema, _, found := strings.Cut(fixture.Relation, "."); found {
+			statements = append(statements, fmt.Sprintf("create schema if not exists %s", schema))
+		}
+		statements = append(statements, fmt.Sprintf("create table %s as (%s)", fixture.Relation, fixture.SQL))
+	}
+	statements = append(statements,
+		fmt.Sprintf("create table %s as (%s)", UNIT_TEST_ACTUAL_TABLE, modelSQL),
+		fmt.Sprintf("create table %s as (%s)", UNIT_TEST_EXPECTED_TABLE, unitTest.ExpectedSQL),
+	)
+	for _, statement := range statements {
+		if _, err := db.Exec(statement); err != nil {
+			log.Error().Str("testName", unitTest.Name).Str("sql", statement).Err(err).Msg("Unit test statement failed")
+			return err
+		}
+	}
+
+	types, err := unitTestColumnTypes(db, UNIT_TEST_ACTUAL_TABLE)
+	if err != nil {
+		return err
+	}
+	for _, column := range unitTest.ExpectedColumns {
+		if _, ok := types[column]; !ok {
+			return fmt.Errorf("the model does not return the expected column %s", column)
+		}
+	}
+
+	missingSQL, unexpectedSQL := UnitTestDiffSQL(unitTest.ExpectedColumns, types)
+	if result.Missing, err = queryUnitTestRows(db, missingSQL); err != nil {
+		return err
+	}
+	result.Unexpected, err = queryUnitTestRows(db, unexpectedSQL)
+	return err
+}
+
+// renderUnitTestModel renders the query of the model like a full build: IsIncremental() is false
+func renderUnitTestModel(dbConnection drivers.DBDriver, asset *SQLModelAsset, unitTest *models.UnitTestDescriptor) (string, error) {
+	vars, err := ParseVars(unitTest.VarsJSON)
+	if err != nil {
+		return "", err
+	}
+	runDate, err := ParseRunDate(unitTest.RunDate)
+	if err != nil {
+		return "", err
+	}
+	options, err := (&RunOptions{Vars: vars, RunDate: runDate}).Resolve(time.Now())
+	if err != nil {
+		return "", err
+	}
+	ctx := &TaskContext{
+		TaskID:        unitTest.Name,
+		Vars:          options.Vars,
+		RunDate:       options.RunDate,
+		IntervalStart: options.IntervalStart,
+		IntervalEnd:   options.IntervalEnd,
+	}
+
+	functions := make(pongo2.Context, len(asset.functions)+1)
+	for name, function := range asset.functions {
+		functions[name] = function
+	}
+	functions["IsIncremental"] = func() bool {
+		return false
+	}
+
+	template, err := pongo2.FromString(asset.descriptor.RawSQL)
+	if err != nil {
+		return "", err
+	}
+	modelSQL, err := template.Execute(MergePongo2Context(
+		FromConnectionContext(dbConnection, nil, asset.descriptor.Name, functions),
+		FromTaskContextPongo2(ctx),
+	))
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(strings.TrimSpace(modelSQL), ";"), nil
+}
+
+// UnitTestDiffSQL returns the queries of the missing and of the unexpected rows. The expected values are cast
+// to the types of the model, the values of the both queries are compared and returned as text.
+func UnitTestDiffSQL(columns []string, types map[string]string) (string, string) {
+	actual := make([]string, len(columns))
+	expected := make([]string, len(columns))
+	text := make([]string, len(columns))
+	for i, column := range columns {
+		quoted := `"` + strings.ReplaceAll(column, `"`, `""`) + `"`
+		actual[i] = quoted
+		expected[i] = fmt.Sprintf("cast(%s as %s) as %s", quoted, types[column], quoted)
+		text[i] = fmt.Sprintf("cast(%s as varchar)", quoted)
+	}
+	selectActual := fmt.Sprintf("select %s from %s", strings.Join(actual, ", "), UNIT_TEST_ACTUAL_TABLE)
+	selectExpected := fmt.Sprintf("select %s from %s", strings.Join(expected, ", "), UNIT_TEST_EXPECTED_TABLE)
+	diff := "select %s from (%s except all %s) as teal_diff order by all"
+	return fmt.Sprintf(diff, strings.Join(text, ", "), selectExpected, selectActual),
+		fmt.Sprintf(diff, strings.Join(text, ", "), selectActual, selectExpected)
+}
+
+func unitTestColumnTypes(db *sql.DB, tableName string) (map[string]string, error) {
+	rows, err := db.Query("select column_name, data_type from information_schema.columns where table_name = $1", tableName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	types := make(map[string]string)
+	for rows.Next() {
+		var name, dataType string
+		if err := rows.Scan(&name, &dataType); err != nil {
+			return nil, err
+		}
+		types[name] = dataType
+	}
+	return types, rows.Err()
+}
+
+func queryUnitTestRows(db *sql.DB, query string) ([][]string, error) {
+	rows, err := db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	var result [][]string
+	for rows.Next() {
+		values := make([]sql.NullString, len(columns))
+		pointers := make([]interface{}, len(columns))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+		if err := rows.Scan(pointers...); err != nil {
+			return nil, err
+		}
+		row := make([]string, len(columns))
+		for i, value := range values {
+			row[i] = "null"
+			if value.Valid {
+				row[i] = value.String
+			}
+		}
+		result = append(result, row)
+	}
+	return result, rows.Err()
+}
+
+// FailedUnitTests counts the failed unit tests
+func FailedUnitTests(results []*UnitTestResult) int {
+	failed := 0
+	for _, result := range results {
+		if result.Status != TestStatusSuccess {
+			failed++
+		}
+	}
+	return failed
+}
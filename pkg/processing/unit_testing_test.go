@@ -0,0 +1,53 @@
+package processing
+
+import (
+	"testing"
+
+	"github.com/go-teal/teal/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnitTestDiffSQL(t *testing.T) {
+	missing, unexpected := UnitTestDiffSQL([]string{"order_id", "created_at"}, map[string]string{"order_id": "BIGINT", "created_at": "DATE"})
+	assert.Equal(t,
+		`select cast("order_id" as varchar), cast("created_at" as varchar) from (`+
+			`select cast("order_id" as BIGINT) as "order_id", cast("created_at" as DATE) as "created_at" from teal_expected`+
+			` except all `+
+			`select "order_id", "created_at" from teal_actual) as teal_diff order by all`,
+		missing)
+	assert.Equal(t,
+		`select cast("order_id" as varchar), cast("created_at" as varchar) from (`+
+			`select "order_id", "created_at" from teal_actual`+
+			` except all `+
+			`select cast("order_id" as BIGINT) as "order_id", cast("created_at" as DATE) as "created_at" from teal_expected) as teal_diff order by all`,
+		unexpected)
+}
+
+func TestUnitTestResultDiff(t *testing.T) {
+	result := &UnitTestResult{Columns: []string{"order_id", "status"}}
+	assert.Empty(t, result.Diff())
+
+	result.Missing = [][]string{{"1", "paid"}}
+	result.Unexpected = [][]string{{"1", "null"}, {"2", "new"}}
+	assert.Equal(t, "  order_id | status\n- 1 | paid\n+ 1 | null\n+ 2 | new\n", result.Diff())
+}
+
+func TestSelectUnitTests(t *testing.T) {
+	unitTests := map[string]*models.UnitTestDescriptor{
+		"dds.orders_totals":   {ModelName: "dds.fact_orders"},
+		"dds.orders_refunds":  {ModelName: "dds.fact_orders"},
+		"mart.revenue_by_day": {ModelName: "mart.revenue"},
+	}
+
+	names, err := SelectUnitTests(unitTests, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"dds.orders_refunds", "dds.orders_totals", "mart.revenue_by_day"}, names)
+
+	names, err = SelectUnitTests(unitTests, []string{"dds.fact_orders", "dds.orders_totals"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"dds.orders_refunds", "dds.orders_totals"}, names)
+
+	_, err = SelectUnitTests(unitTests, []string{"mart.revenue", "dds.unknown"})
+	assert.EqualError(t, err, "no unit tests of dds.unknown")
+}
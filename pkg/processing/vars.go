@@ -0,0 +1,103 @@
+package processing
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// varDefaults holds the vars of profile.yaml, registered by the generated code
+var varDefaults = struct {
+	sync.RWMutex
+	vars map[string]interface{}
+}{
+	vars: make(map[string]interface{}),
+}
+
+// ParseVars decodes vars from a JSON object, e.g. the --vars flag.
+// Whole numbers are decoded as int64, other numbers as float64.
+func ParseVars(varsJSON string) (map[string]interface{}, error) {
+	vars := make(map[string]interface{})
+	if strings.TrimSpace(varsJSON) == "" {
+		return vars, nil
+	}
+	decoder := json.NewDecoder(bytes.NewReader([]byte(varsJSON)))
+	decoder.UseNumber()
+	if err := decoder.Decode(&vars); err != nil {
+		return nil, fmt.Errorf("vars must be a JSON object: %w", err)
+	}
+	for name, value := range vars {
+		vars[name] = normalizeVarValue(value)
+	}
+	return vars, nil
+}
+
+func normalizeVarValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	case []interface{}:
+		for i := range v {
+			v[i] = normalizeVarValue(v[i])
+		}
+	case map[string]interface{}:
+		for key := range v {
+			v[key] = normalizeVarValue(v[key])
+		}
+	}
+	return value
+}
+
+// MustRegisterVars registers the vars of profile.yaml as a JSON object, it is called by the generated code
+func MustRegisterVars(varsJSON string) {
+	vars, err := ParseVars(varsJSON)
+	if err != nil {
+		panic(err)
+	}
+	varDefaults.Lock()
+	defer varDefaults.Unlock()
+	varDefaults.vars = vars
+}
+
+// ResolveVars merges the overrides into the vars of profile.yaml, overrides of undeclared vars are rejected
+func ResolveVars(overrides map[string]interface{}) (map[string]interface{}, error) {
+	varDefaults.RLock()
+	defer varDefaults.RUnlock()
+	vars := make(map[string]interface{}, len(varDefaults.vars))
+	for name, value := range varDefaults.vars {
+		vars[name] = value
+	}
+	var unknown []string
+	for name, value := range overrides {
+		if _, ok := varDefaults.vars[name]; !ok {
+			unknown = append(unknown, name)
+			continue
+		}
+		vars[name] = normalizeVarValue(value)
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, fmt.Errorf("unknown vars %s, vars must be declared in profile.yaml", strings.Join(unknown, ", "))
+	}
+	return vars, nil
+}
+
+// varFunction is the Var("name", default) template function
+func varFunction(vars map[string]interface{}) func(name string, defaultValue ...interface{}) (interface{}, error) {
+	return func(name string, defaultValue ...interface{}) (interface{}, error) {
+		if value, ok := vars[name]; ok {
+			return value, nil
+		}
+		if len(defaultValue) > 0 {
+			return defaultValue[0], nil
+		}
+		return nil, fmt.Errorf("var %s is not defined, declare it in the vars of profile.yaml or pass a default", name)
+	}
+}
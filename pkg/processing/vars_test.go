@@ -0,0 +1,43 @@
+package processing
+
+import (
+	"testing"
+
+	pongo2 "github.com/flosch/pongo2/v6"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVarsResolveOverridesAndDefaults(t *testing.T) {
+	MustRegisterVars(`{"start_date": "2024-01-01", "limit": 10, "ratio": 0.5, "countries": ["NL", "US"]}`)
+	defer MustRegisterVars(`{}`)
+
+	overrides, err := ParseVars(`{"limit": 25}`)
+	require.NoError(t, err)
+	vars, err := ResolveVars(overrides)
+	require.NoError(t, err)
+	assert.Equal(t, int64(25), vars["limit"])
+	assert.Equal(t, 0.5, vars["ratio"])
+	assert.Equal(t, "2024-01-01", vars["start_date"])
+
+	_, err = ResolveVars(map[string]interface{}{"limt": 1})
+	assert.EqualError(t, err, "unknown vars limt, vars must be declared in profile.yaml")
+	_, err = ParseVars(`[1]`)
+	assert.Error(t, err)
+
+	render := func(source string, ctx *TaskContext) (string, error) {
+		tpl, err := pongo2.FromString(source)
+		require.NoError(t, err)
+		return tpl.Execute(FromTaskContextPongo2(ctx))
+	}
+	output, err := render(`{{ Var("start_date") }}/{{ Var("limit") }}/{{ Var("missing", 7) }}{% for c in Var("countries") %}/{{ c }}{% endfor %}`, &TaskContext{Vars: vars})
+	require.NoError(t, err)
+	assert.Equal(t, "2024-01-01/25/7/NL/US", output)
+
+	output, err = render(`{{ Var("limit") }}`, &TaskContext{})
+	require.NoError(t, err)
+	assert.Equal(t, "10", output, "tasks without vars use the defaults")
+
+	_, err = render(`{{ Var("missing") }}`, &TaskContext{Vars: vars})
+	assert.ErrorContains(t, err, "var missing is not defined")
+}
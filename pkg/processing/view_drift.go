@@ -0,0 +1,289 @@
+package processing
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync/atomic"
+
+	pongo2 "github.com/flosch/pongo2/v6"
+	"github.com/go-teal/teal/pkg/configs"
+	"github.com/go-teal/teal/pkg/drivers"
+	"github.com/rs/zerolog/log"
+)
+
+// VIEW_FINGERPRINT_PREFIX marks the comment teal puts on a view to store the fingerprint of its SQL
+const VIEW_FINGERPRINT_PREFIX = "teal:fingerprint="
+
+// VIEW_REPLACE_SAVEPOINT lets an atomic execution fall back to drop + create after a failed replace
+const VIEW_REPLACE_SAVEPOINT = "teal_replace_view"
+
+var failOnViewDrift atomic.Bool
+
+// SetFailOnViewDrift makes every view model fail on drift instead of replacing the view,
+// regardless of its on_view_drift setting. Intended for production runs.
+func SetFailOnViewDrift(fail bool) {
+	failOnViewDrift.Store(fail)
+}
+
+// viewFingerprintComment hashes the generated view SQL before rendering,
+// so the vars, the run date and the task of a run do not change the fingerprint
+func viewFingerprintComment(viewSQL string) string {
+	hash := sha256.Sum256([]byte(strings.TrimSpace(viewSQL)))
+	return VIEW_FINGERPRINT_PREFIX + hex.EncodeToString(hash[:])
+}
+
+func commentOnViewSQL(viewName string, comment string) string {
+	return fmt.Sprintf("comment on view %s is '%s'", viewName, comment)
+}
+
+// syncView compares the fingerprint stored on the existing view with the generated view SQL
+// and replaces the view (or fails, depending on the drift policy) when they differ.
+// A view without a fingerprint (created by hand or by an earlier version) is adopted: only the fingerprint is stored.
+func (s *SQLModelAsset) syncView(ctx *TaskContext) error {
+	if s.descriptor.ReplaceViewSQL == "" {
+		// Assets generated before drift detection
+		return nil
+	}
+	dbConnection := s.getDBConnection()
+
+	tx, err := dbConnection.Begin()
+	if err != nil {
+		log.Error().Caller().
+			Str("taskId", ctx.TaskID).
+			Str("taskUUID", ctx.TaskUUID).
+			Str("assetName", s.descriptor.Name).
+			Err(err).
+			Msg("Failed to begin transaction")
+		defer dbConnection.Rollback(tx)
+		return err
+	}
+
+	fingerprint := viewFingerprintComment(s.descriptor.CreateViewSQL)
+	comment, err := dbConnection.GetRelationComment(tx, s.descriptor.Name)
+	if err != nil {
+		defer dbConnection.Rollback(tx)
+		log.Error().Caller().
+			Str("taskId", ctx.TaskID).
+			Str("taskUUID", ctx.TaskUUID).
+			Str("assetName", s.descriptor.Name).
+			Err(err).
+			Msg("Failed to read the view fingerprint")
+		return err
+	}
+	if comment == fingerprint {
+		return dbConnection.Commit(tx)
+	}
+
+	if !strings.HasPrefix(comment, VIEW_FINGERPRINT_PREFIX) {
+		log.Info().
+			Str("taskId", ctx.TaskID).
+			Str("taskUUID", ctx.TaskUUID).
+			Str("assetName", s.descriptor.Name).
+			Msg("View has no fingerprint, adopting the view")
+		err = dbConnection.Exec(tx, commentOnViewSQL(s.descriptor.Name, fingerprint))
+		if err != nil {
+			defer dbConnection.Rollback(tx)
+			log.Error().Caller().
+				Str("taskId", ctx.TaskID).
+				Str("taskUUID", ctx.TaskUUID).
+				Str("assetName", s.descriptor.Name).
+				Err(err).
+				Msg("Failed to store the view fingerprint")
+			return err
+		}
+		return dbConnection.Commit(tx)
+	}
+
+	if failOnViewDrift.Load() || s.descriptor.ModelProfile.OnViewDrift == configs.VIEW_DRIFT_FAIL {
+		defer dbConnection.Rollback(tx)
+		err = fmt.Errorf("view %s has drifted from its model: stored %q, expected %q", s.descriptor.Name, comment, fingerprint)
+		log.Error().Caller().
+			Str("taskId", ctx.TaskID).
+			Str("taskUUID", ctx.TaskUUID).
+			Str("assetName", s.descriptor.Name).
+			Err(err).
+			Msg("View drift detected")
+		return err
+	}
+
+	log.Info().
+		Str("taskId", ctx.TaskID).
+		Str("taskUUID", ctx.TaskUUID).
+		Str("assetName", s.descriptor.Name).
+		Msg("View drift detected, replacing the view")
+
+	tx, err = s.replaceView(ctx, dbConnection, tx)
+	if err != nil {
+		defer dbConnection.Rollback(tx)
+		return err
+	}
+	sqlQuery := commentOnViewSQL(s.descriptor.Name, fingerprint)
+	err = dbConnection.Exec(tx, sqlQuery)
+	if err != nil {
+		defer dbConnection.Rollback(tx)
+		log.Error().Caller().Stack().
+			Str("taskId", ctx.TaskID).
+			Str("taskUUID", ctx.TaskUUID).
+			Str("assetName", s.descriptor.Name).
+			Str("sql", sqlQuery).
+			Err(err).
+			Msg("Failed to store the view fingerprint")
+		return err
+	}
+	return dbConnection.Commit(tx)
+}
+
+// replaceView replaces the view with CREATE OR REPLACE VIEW. PostgreSQL rejects it when the columns
+// of the view change, the view is then dropped and created again: in a new transaction, or after
+// a rollback to a savepoint in the shared transaction of an atomic execution (PostgreSQL only).
+// Returns the transaction holding the new view.
+func (s *SQLModelAsset) replaceView(ctx *TaskContext, dbConnection drivers.DBDriver, tx interface{}) (interface{}, error) {
+	context := MergePongo2Context(
+		FromConnectionContext(dbConnection, tx, s.descriptor.Name, s.functions),
+		FromTaskContextPongo2(ctx),
+	)
+	// The replace, drop and create statements
+	sqlTemplates := []string{s.descriptor.ReplaceViewSQL, s.descriptor.DropViewSQL, s.descriptor.CreateViewSQL}
+	statements := make([]string, len(sqlTemplates))
+	for i, sqlTemplate := range sqlTemplates {
+		sqlQuery, err := renderSQL(sqlTemplate, context)
+		if err != nil {
+			log.Error().Caller().Stack().
+				Str("taskId", ctx.TaskID).
+				Str("taskUUID", ctx.TaskUUID).
+				Str("assetName", s.descriptor.Name).
+				Str("sql", sqlTemplate).
+				Err(err).
+				Msg("Failed to render view SQL")
+			return tx, err
+		}
+		statements[i] = sqlQuery
+	}
+
+	isAtomic := s.atomicConnection != nil
+	if isAtomic {
+		if err := dbConnection.Exec(tx, "savepoint "+VIEW_REPLACE_SAVEPOINT); err != nil {
+			return tx, err
+		}
+	}
+	err := dbConnection.Exec(tx, statements[0])
+	if err == nil {
+		if isAtomic {
+			err = dbConnection.Exec(tx, "release savepoint "+VIEW_REPLACE_SAVEPOINT)
+		}
+		return tx, err
+	}
+	log.Warn().
+		Str("taskId", ctx.TaskID).
+		Str("taskUUID", ctx.TaskUUID).
+		Str("assetName", s.descriptor.Name).
+		Err(err).
+		Msg("Failed to replace the view, dropping and creating it")
+
+	if isAtomic {
+		if err = dbConnection.Exec(tx, "rollback to savepoint "+VIEW_REPLACE_SAVEPOINT); err != nil {
+			return tx, err
+		}
+	} else {
+		dbConnection.Rollback(tx)
+		if tx, err = dbConnection.Begin(); err != nil {
+			return tx, err
+		}
+	}
+	for _, sqlQuery := range statements[1:] {
+		if err = dbConnection.Exec(tx, sqlQuery); err != nil {
+			log.Error().Caller().Stack().
+				Str("taskId", ctx.TaskID).
+				Str("taskUUID", ctx.TaskUUID).
+				Str("assetName", s.descriptor.Name).
+				Str("sql", sqlQuery).
+				Err(err).
+				Msg("Failed to replace view")
+			return tx, err
+		}
+	}
+	return tx, nil
+}
+
+func commentOnMaterializedViewSQL(viewName string, comment string) string {
+	return fmt.Sprintf("comment on materialized view %s is '%s'", viewName, comment)
+}
+
+// syncMaterializedView creates the materialized view or refreshes the existing one. REFRESH keeps the stored
+// definition, so the fingerprint of the generated SQL is kept in the comment like for views, and a view
+// with another fingerprint is dropped and created again. A view without a fingerprint is adopted and refreshed.
+func (s *SQLModelAsset) syncMaterializedView(ctx *TaskContext, exists bool) error {
+	fingerprint := viewFingerprintComment(s.descriptor.CreateMaterializedViewSQL)
+	storeFingerprint := commentOnMaterializedViewSQL(s.descriptor.Name, fingerprint)
+	if !exists {
+		return s.execModelSQL(ctx, joinStatements(s.descriptor.CreateMaterializedViewSQL, storeFingerprint), "create materialized view")
+	}
+
+	dbConnection := s.getDBConnection()
+	tx, err := dbConnection.Begin()
+	if err != nil {
+		log.Error().Caller().
+			Str("taskId", ctx.TaskID).
+			Str("taskUUID", ctx.TaskUUID).
+			Str("assetName", s.descriptor.Name).
+			Err(err).
+			Msg("Failed to begin transaction")
+		defer dbConnection.Rollback(tx)
+		return err
+	}
+	comment, err := dbConnection.GetRelationComment(tx, s.descriptor.Name)
+	if err != nil {
+		defer dbConnection.Rollback(tx)
+		log.Error().Caller().
+			Str("taskId", ctx.TaskID).
+			Str("taskUUID", ctx.TaskUUID).
+			Str("assetName", s.descriptor.Name).
+			Err(err).
+			Msg("Failed to read the materialized view fingerprint")
+		return err
+	}
+	if err = dbConnection.Commit(tx); err != nil {
+		return err
+	}
+
+	if comment == fingerprint {
+		return s.execModelSQL(ctx, s.descriptor.RefreshMaterializedViewSQL, "refresh materialized view")
+	}
+	if !strings.HasPrefix(comment, VIEW_FINGERPRINT_PREFIX) {
+		log.Info().
+			Str("taskId", ctx.TaskID).
+			Str("taskUUID", ctx.TaskUUID).
+			Str("assetName", s.descriptor.Name).
+			Msg("Materialized view has no fingerprint, adopting the view")
+		return s.execModelSQL(ctx, joinStatements(s.descriptor.RefreshMaterializedViewSQL, storeFingerprint), "refresh materialized view")
+	}
+	log.Info().
+		Str("taskId", ctx.TaskID).
+		Str("taskUUID", ctx.TaskUUID).
+		Str("assetName", s.descriptor.Name).
+		Msg("Materialized view definition has changed, recreating the view")
+	return s.execModelSQL(ctx, joinStatements(
+		s.descriptor.DropMaterializedViewSQL,
+		s.descriptor.CreateMaterializedViewSQL,
+		storeFingerprint,
+	), "recreate materialized view")
+}
+
+// joinStatements joins SQL statements into one script executed at once
+func joinStatements(statements ...string) string {
+	trimmed := make([]string, len(statements))
+	for i, statement := range statements {
+		trimmed[i] = strings.TrimSuffix(strings.TrimSpace(statement), ";")
+	}
+	return strings.Join(trimmed, ";\n") + ";"
+}
+
+func renderSQL(sqlTemplate string, context pongo2.Context) (string, error) {
+	templ, err := pongo2.FromString(sqlTemplate)
+	if err != nil {
+		return "", err
+	}
+	return templ.Execute(context)
+}
@@ -0,0 +1,149 @@
+package processing
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-teal/teal/pkg/configs"
+	"github.com/go-teal/teal/pkg/drivers"
+	"github.com/go-teal/teal/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func viewAsset(connection string) *SQLModelAsset {
+	return InitSQLModelAsset(&models.SQLModelDescriptor{
+		Name:           "dds.active_users",
+		CreateViewSQL:  "create view dds.active_users as (select id, '{{ TaskID() }}' as task_id from dds.users)",
+		ReplaceViewSQL: "create or replace view dds.active_users as (select id, '{{ TaskID() }}' as task_id from dds.users)",
+		DropViewSQL:    "drop view dds.active_users",
+		ModelProfile:   &configs.ModelProfile{Connection: connection, Materialization: configs.MAT_VIEW},
+	}).(*SQLModelAsset)
+}
+
+func TestViewFingerprintIgnoresRuntimeValues(t *testing.T) {
+	driver := useRecordingDriver("view_fingerprint")
+	asset := viewAsset("view_fingerprint")
+	driver.comments["dds.active_users"] = viewFingerprintComment(asset.descriptor.CreateViewSQL)
+
+	assert.NoError(t, asset.syncView(&TaskContext{TaskID: "first_run"}))
+	assert.NoError(t, asset.syncView(&TaskContext{TaskID: "second_run"}))
+	assert.Empty(t, driver.statements)
+	assert.Equal(t, 2, driver.commits)
+}
+
+func TestSyncViewAdoptsViewWithoutFingerprint(t *testing.T) {
+	driver := useRecordingDriver("view_adopt")
+	asset := viewAsset("view_adopt")
+
+	assert.NoError(t, asset.syncView(&TaskContext{TaskID: "run"}))
+	assert.Equal(t, []string{
+		commentOnViewSQL("dds.active_users", viewFingerprintComment(asset.descriptor.CreateViewSQL)),
+	}, driver.statements)
+}
+
+func TestSyncViewReplacesDriftedView(t *testing.T) {
+	driver := useRecordingDriver("view_replace")
+	asset := viewAsset("view_replace")
+	driver.comments["dds.active_users"] = VIEW_FINGERPRINT_PREFIX + "stale"
+
+	assert.NoError(t, asset.syncView(&TaskContext{TaskID: "run"}))
+	assert.Equal(t, []string{
+		"create or replace view dds.active_users as (select id, 'run' as task_id from dds.users)",
+		commentOnViewSQL("dds.active_users", viewFingerprintComment(asset.descriptor.CreateViewSQL)),
+	}, driver.statements)
+	assert.Equal(t, 1, driver.commits)
+}
+
+func TestSyncViewDropsAndCreatesWhenReplaceFails(t *testing.T) {
+	driver := useRecordingDriver("view_recreate")
+	asset := viewAsset("view_recreate")
+	driver.comments["dds.active_users"] = VIEW_FINGERPRINT_PREFIX + "stale"
+	driver.failures["create or replace view"] = errors.New("cannot drop columns from view")
+
+	assert.NoError(t, asset.syncView(&TaskContext{TaskID: "run"}))
+	assert.Equal(t, []string{
+		"create or replace view dds.active_users as (select id, 'run' as task_id from dds.users)",
+		"drop view dds.active_users",
+		"create view dds.active_users as (select id, 'run' as task_id from dds.users)",
+		commentOnViewSQL("dds.active_users", viewFingerprintComment(asset.descriptor.CreateViewSQL)),
+	}, driver.statements)
+	assert.Equal(t, 1, driver.rollbacks)
+	assert.Equal(t, 1, driver.commits)
+}
+
+func TestSyncViewRecreatesInAtomicTransaction(t *testing.T) {
+	driver := useRecordingDriver("view_atomic")
+	asset := viewAsset("view_atomic")
+	driver.comments["dds.active_users"] = VIEW_FINGERPRINT_PREFIX + "stale"
+	driver.failures["create or replace view"] = errors.New("cannot drop columns from view")
+
+	err := runAtomic(driver, func(dbConnection drivers.DBDriver) error {
+		asset.atomicConnection = dbConnection
+		defer func() { asset.atomicConnection = nil }()
+		return asset.syncView(&TaskContext{TaskID: "run"})
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"savepoint teal_replace_view",
+		"create or replace view dds.active_users as (select id, 'run' as task_id from dds.users)",
+		"rollback to savepoint teal_replace_view",
+		"drop view dds.active_users",
+		"create view dds.active_users as (select id, 'run' as task_id from dds.users)",
+		commentOnViewSQL("dds.active_users", viewFingerprintComment(asset.descriptor.CreateViewSQL)),
+	}, driver.statements)
+	assert.Equal(t, 0, driver.rollbacks)
+	assert.Equal(t, 1, driver.commits)
+}
+
+func TestSyncViewFailsOnDriftWithFailPolicy(t *testing.T) {
+	driver := useRecordingDriver("view_fail")
+	asset := viewAsset("view_fail")
+	asset.descriptor.ModelProfile.OnViewDrift = configs.VIEW_DRIFT_FAIL
+	driver.comments["dds.active_users"] = VIEW_FINGERPRINT_PREFIX + "stale"
+
+	assert.Error(t, asset.syncView(&TaskContext{TaskID: "run"}))
+	assert.Empty(t, driver.statements)
+	assert.Equal(t, 1, driver.rollbacks)
+}
+
+func materializedViewAsset(connection string) *SQLModelAsset {
+	return InitSQLModelAsset(&models.SQLModelDescriptor{
+		Name:                       "mart.revenue",
+		CreateMaterializedViewSQL:  "create materialized view mart.revenue\nas (select day, sum(amount) as amount from dds.orders group by day);\ncreate unique index revenue_pkey on mart.revenue (day);\n\n",
+		RefreshMaterializedViewSQL: "refresh materialized view concurrently mart.revenue",
+		DropMaterializedViewSQL:    "drop materialized view mart.revenue",
+		ModelProfile:               &configs.ModelProfile{Connection: connection, Materialization: configs.MAT_MATERIALIZED_VIEW},
+	}).(*SQLModelAsset)
+}
+
+func TestSyncMaterializedView(t *testing.T) {
+	create := "create materialized view mart.revenue\nas (select day, sum(amount) as amount from dds.orders group by day);\n" +
+		"create unique index revenue_pkey on mart.revenue (day)"
+	refresh := "refresh materialized view concurrently mart.revenue"
+	fingerprint := viewFingerprintComment(materializedViewAsset("").descriptor.CreateMaterializedViewSQL)
+	storeFingerprint := "comment on materialized view mart.revenue is '" + fingerprint + "';"
+
+	cases := []struct {
+		name    string
+		exists  bool
+		comment string
+		want    string
+	}{
+		{name: "create", exists: false, want: create + ";\n" + storeFingerprint},
+		{name: "refresh", exists: true, comment: fingerprint, want: refresh},
+		{name: "adopt", exists: true, comment: "", want: refresh + ";\n" + storeFingerprint},
+		{name: "recreate", exists: true, comment: VIEW_FINGERPRINT_PREFIX + "stale",
+			want: "drop materialized view mart.revenue;\n" + create + ";\n" + storeFingerprint},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			driver := useRecordingDriver("materialized_view_" + tc.name)
+			asset := materializedViewAsset("materialized_view_" + tc.name)
+			driver.comments["mart.revenue"] = tc.comment
+
+			assert.NoError(t, asset.syncMaterializedView(&TaskContext{}, tc.exists))
+			assert.Equal(t, []string{tc.want}, driver.statements)
+		})
+	}
+}
@@ -2,6 +2,7 @@ package debugging
 
 import (
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"sort"
 	"strings"
@@ -66,8 +67,10 @@ func (s *DebuggingService) GetDagNodes() []DagNodeDTO {
 			if snap, ok := s.dag.NodeRuntimeStateFor(name); ok {
 				node.State = NodeState(snap.State) // Convert dags.NodeState to debugging.NodeState
 				node.SuccessfulTests = snap.TestsPassed
+				node.WarnedTests = snap.TestsWarned
 				node.LastExecutionDuration = snap.LastExecutionDuration
 				node.LastTestsDuration = snap.LastTestsDuration
+				node.Attempts = snap.Attempts
 				// TotalTests will be set below from model profile
 			}
 
@@ -92,10 +95,12 @@ func (s *DebuggingService) GetDagNodes() []DagNodeDTO {
 				switch node.Materialization {
 				case MaterializationView:
 					node.SQLCompiledQuery = strings.TrimSpace(desc.CreateViewSQL)
+				case MaterializationMaterializedView:
+					node.SQLCompiledQuery = strings.TrimSpace(desc.CreateMaterializedViewSQL)
 				case MaterializationTable, MaterializationIncremental:
 					node.SQLCompiledQuery = strings.TrimSpace(desc.InsertSQL)
-				case MaterializationCustom:
-					// Custom materialization uses RawSQL directly
+				case MaterializationCustom, MaterializationExport:
+					// Custom and export materializations use RawSQL directly
 					node.SQLCompiledQuery = strings.TrimSpace(desc.RawSQL)
 				default:
 					node.SQLCompiledQuery = strings.TrimSpace(desc.InsertSQL)
@@ -124,6 +129,30 @@ func (s *DebuggingService) GetDagNodes() []DagNodeDTO {
 						}
 					}
 				}
+				// DuckDB ignores grants
+				if node.ConnectionType == "postgres" && desc.ModelProfile.Materialization != configs.MAT_CUSTOM && desc.ModelProfile.Materialization != configs.MAT_EXPORT {
+					node.Grants = desc.ModelProfile.Grants
+				}
+				node.Atomic = desc.ModelProfile.Atomic
+				node.Retries = desc.ModelProfile.Retries
+				node.Tags = desc.ModelProfile.Tags
+				node.Disabled = !desc.ModelProfile.IsEnabled()
+				if desc.ModelProfile.Export != nil {
+					node.Export = &ExportDTO{
+						Format:      desc.ModelProfile.Export.Format,
+						Path:        desc.ModelProfile.Export.Path,
+						PartitionBy: desc.ModelProfile.Export.PartitionBy,
+					}
+				}
+				for _, column := range desc.ModelProfile.Columns {
+					node.Columns = append(node.Columns, ColumnDTO{
+						Name:        column.Name,
+						Type:        column.Type,
+						Nullable:    column.Nullable,
+						Description: column.Description,
+					})
+				}
+				node.EnforceTypes = desc.ModelProfile.EnforceTypes
 
 			case *models.RawModelDescriptor:
 				node.Materialization = MaterializationRaw
@@ -140,6 +169,9 @@ func (s *DebuggingService) GetDagNodes() []DagNodeDTO {
 				node.ConnectionName = desc.ModelProfile.Connection
 				node.IsDataFramed = desc.ModelProfile.IsDataFramed
 				node.PersistInputs = desc.ModelProfile.PersistInputs
+				node.Retries = desc.ModelProfile.Retries
+				node.Tags = desc.ModelProfile.Tags
+				node.Disabled = !desc.ModelProfile.IsEnabled()
 
 				// Add tests from model profile
 				if desc.ModelProfile.Tests != nil {
@@ -160,6 +192,78 @@ func (s *DebuggingService) GetDagNodes() []DagNodeDTO {
 						}
 					}
 				}
+
+			case *models.SeedDescriptor:
+				node.Materialization = MaterializationSeed
+				node.Retries = desc.ModelProfile.Retries
+				node.Tags = desc.ModelProfile.Tags
+				node.Disabled = !desc.ModelProfile.IsEnabled()
+				// Decode base64 encoded description if present
+				if desc.ModelProfile.Description != "" {
+					decoded, err := base64.StdEncoding.DecodeString(desc.ModelProfile.Description)
+					if err == nil {
+						node.Description = string(decoded)
+					} else {
+						// Fall back to raw description if decode fails
+						node.Description = desc.ModelProfile.Description
+					}
+				}
+				node.ConnectionName = desc.ModelProfile.Connection
+
+				// Add tests from model profile
+				if desc.ModelProfile.Tests != nil {
+					node.Tests = make([]string, 0, len(desc.ModelProfile.Tests))
+					for _, test := range desc.ModelProfile.Tests {
+						node.Tests = append(node.Tests, test.Name)
+					}
+					node.TotalTests = len(desc.ModelProfile.Tests)
+				}
+
+				// Find connection type from config
+				if s.dag.Config != nil {
+					for _, conn := range s.dag.Config.Connections {
+						if conn.Name == desc.ModelProfile.Connection {
+							node.ConnectionType = conn.Type
+							break
+						}
+					}
+				}
+				// DuckDB ignores grants
+				if node.ConnectionType == "postgres" {
+					node.Grants = desc.ModelProfile.Grants
+				}
+
+			case *models.SourceDescriptor:
+				node.Materialization = MaterializationSource
+				// Decode base64 encoded description if present
+				if desc.SourceProfile.Description != "" {
+					decoded, err := base64.StdEncoding.DecodeString(desc.SourceProfile.Description)
+					if err == nil {
+						node.Description = string(decoded)
+					} else {
+						// Fall back to raw description if decode fails
+						node.Description = desc.SourceProfile.Description
+					}
+				}
+				node.ConnectionName = desc.SourceProfile.Connection
+				node.SourceRelation = desc.SourceProfile.GetRelationName()
+				if desc.SourceProfile.Freshness != nil {
+					node.Freshness = &FreshnessDTO{
+						LoadedAtField: desc.SourceProfile.LoadedAtField,
+						WarnAfter:     desc.SourceProfile.Freshness.WarnAfter,
+						ErrorAfter:    desc.SourceProfile.Freshness.ErrorAfter,
+					}
+				}
+
+				// Find connection type from config
+				if s.dag.Config != nil {
+					for _, conn := range s.dag.Config.Connections {
+						if conn.Name == desc.SourceProfile.Connection {
+							node.ConnectionType = conn.Type
+							break
+						}
+					}
+				}
 			}
 
 			nodes = append(nodes, node)
@@ -205,6 +309,9 @@ func (s *DebuggingService) GetTestProfiles() []TestProfileDTO {
 					}
 				}
 				testDTO.ConnectionName = desc.TestProfile.Connection
+				testDTO.Severity = desc.TestProfile.Severity
+				testDTO.ErrorIf = desc.TestProfile.ErrorIf
+				testDTO.WarnIf = desc.TestProfile.WarnIf
 
 				// Find connection type from config
 				if s.dag.Config != nil {
@@ -347,6 +454,7 @@ func (s *DebuggingService) GetDagExecutionStatus(taskId string) DagExecutionResp
 					if len(node.Tests) > 0 {
 						taskStatus.TotalTests = len(node.Tests)
 						taskStatus.PassedTests = node.TestsPassed
+						taskStatus.WarnedTests = node.TestsWarned
 						taskStatus.FailedTests = node.TestsFailed
 					}
 
@@ -436,7 +544,15 @@ func (s *DebuggingService) GetDagExecutionStatus(taskId string) DagExecutionResp
 	}
 }
 
-func (s *DebuggingService) ExecuteDag(taskId string, data map[string]interface{}) <-chan DagExecutionResponseDTO {
+// ValidateRunOptions checks the options against the assets of the DAG
+func (s *DebuggingService) ValidateRunOptions(options *processing.RunOptions) error {
+	if s.dag == nil {
+		return nil
+	}
+	return options.ValidateFullRefresh(s.dag.AssetsMap)
+}
+
+func (s *DebuggingService) ExecuteDag(taskId string, data map[string]interface{}, options *processing.RunOptions) <-chan DagExecutionResponseDTO {
 	responseChan := make(chan DagExecutionResponseDTO, 1)
 
 	if s.dag == nil {
@@ -489,7 +605,7 @@ func (s *DebuggingService) ExecuteDag(taskId string, data map[string]interface{}
 	dagResultChan := make(chan map[string]interface{}, 1)
 
 	// Start the DAG execution
-	s.dag.Push(taskId, data, dagResultChan)
+	s.dag.PushWithOptions(taskId, data, options, dagResultChan)
 
 	// Monitor execution in a goroutine
 	go func() {
@@ -754,6 +870,7 @@ func (s *DebuggingService) MutateAsset(assetName string, taskId string, taskUUID
 			// Update node state
 			node.State = dags.NodeStateInProgress
 			node.StartTime = &startTime
+			node.Attempts = 1
 
 			// Store initial in-progress state
 			s.storeAssetExecutionMetadata(taskId, assetName, response)
@@ -767,7 +884,10 @@ func (s *DebuggingService) MutateAsset(assetName string, taskId string, taskUUID
 				InstanceUUID: s.dag.DagInstanceUUID,
 				Input:        inputData,
 			}
-			result, err := node.Asset.Execute(ctx)
+			result, attempts, err := processing.ExecuteWithRetries(ctx, node.Asset, func(attempt int, err error) {
+				node.Attempts = attempt
+			})
+			response.Attempts = attempts
 
 			endTime := time.Now()
 			endTimeMs := endTime.UnixMilli()
@@ -783,6 +903,7 @@ func (s *DebuggingService) MutateAsset(assetName string, taskId string, taskUUID
 				node.LastError = err
 				response.Status = NodeStateFailed
 				response.Error = err.Error()
+				response.RolledBack = errors.Is(err, processing.ErrAtomicRollback)
 			} else {
 				node.State = dags.NodeStateSuccess
 				node.LastResult = result // Store in node for downstream access
@@ -1350,7 +1471,8 @@ func (s *DebuggingService) GetConnectionStatus() ConnectionStatusResponseDTO {
 
 // ExecuteTest executes a single test query and stores the result
 // Test succeeds (status: SUCCESS) if query returns ZERO rows
-// Test fails (status: FAILED) if query returns ONE OR MORE rows
+// Test fails (status: FAILED) if query returns ONE OR MORE rows,
+// the severity, error_if and warn_if of the test profile may turn the failure into a warning (status: WARN)
 func (s *DebuggingService) ExecuteTest(testName, taskId string) <-chan TestExecuteResponseDTO {
 	responseChan := make(chan TestExecuteResponseDTO, 1)
 
@@ -1460,8 +1582,19 @@ func (s *DebuggingService) ExecuteTest(testName, taskId string) <-chan TestExecu
 			Str("sql", renderedSQL).
 			Msg("Executing test query")
 
-		// Execute test query as DataFrame to get rows
-		df, err := dbConnection.ToDataFrame(renderedSQL)
+		// Execute test query as DataFrame to get rows. Tests of a data framed raw asset
+		// read its last DataFrame, which is persisted only while the test runs.
+		var df *dataframe.DataFrame
+		query := func() {
+			df, err = dbConnection.ToDataFrame(renderedSQL)
+		}
+		if rawAsset := s.dataFramedRawAsset(testName); rawAsset != nil {
+			if persistErr := rawAsset.WithPersistedResult(taskContext, query); persistErr != nil {
+				err = persistErr
+			}
+		} else {
+			query()
+		}
 
 		endTime := time.Now()
 		response.DurationMs = endTime.Sub(startTime).Milliseconds()
@@ -1490,17 +1623,29 @@ func (s *DebuggingService) ExecuteTest(testName, taskId string) <-chan TestExecu
 		rowCount := df.Nrow()
 		response.RowCount = rowCount
 
-		// Determine test status: 0 rows = SUCCESS, >0 rows = FAILED
-		if rowCount == 0 {
-			response.Status = "SUCCESS"
+		// Determine test status by the severity of the test, by default 0 rows = SUCCESS, >0 rows = FAILED
+		testStatus, _ := processing.EvaluateTestSeverity(sqlTestDesc.TestProfile, int64(rowCount))
+		switch testStatus {
+		case processing.TestStatusSuccess:
+			response.Status = string(TestStatusSuccess)
 			log.Debug().
 				Str("taskId", taskId).
 				Str("taskUUID", taskUUID).
 				Str("testName", testName).
+				Int("rowCount", rowCount).
 				Int64("durationMs", response.DurationMs).
-				Msg("Test passed (0 rows returned)")
-		} else {
-			response.Status = "FAILED"
+				Msg("Test passed")
+		case processing.TestStatusWarn:
+			response.Status = string(TestStatusWarn)
+			log.Warn().
+				Str("taskId", taskId).
+				Str("taskUUID", taskUUID).
+				Str("testName", testName).
+				Int("rowCount", rowCount).
+				Int64("durationMs", response.DurationMs).
+				Msg("Test warned")
+		default:
+			response.Status = string(TestStatusFailed)
 			log.Warn().
 				Str("taskId", taskId).
 				Str("taskUUID", taskUUID).
@@ -1546,6 +1691,26 @@ func (s *DebuggingService) ExecuteTest(testName, taskId string) <-chan TestExecu
 	return responseChan
 }
 
+// dataFramedRawAsset returns the data framed raw asset tested by the test, nil for tests of other assets
+func (s *DebuggingService) dataFramedRawAsset(testName string) *processing.RawModelAsset {
+	for _, asset := range s.dag.AssetsMap {
+		rawAsset, ok := asset.(*processing.RawModelAsset)
+		if !ok {
+			continue
+		}
+		modelProfile := rawAsset.GetDescriptor().(*models.RawModelDescriptor).ModelProfile
+		if !modelProfile.IsDataFramed {
+			continue
+		}
+		for _, test := range modelProfile.Tests {
+			if test.Name == testName {
+				return rawAsset
+			}
+		}
+	}
+	return nil
+}
+
 // GetTestData retrieves test execution data for a specific task
 func (s *DebuggingService) GetTestData(testName, taskId string) TestDataResponseDTO {
 	s.mu.RLock()
@@ -8,6 +8,11 @@ const (
 	MaterializationView        MaterializationType = "view"
 	MaterializationCustom      MaterializationType = "custom"
 	MaterializationRaw         MaterializationType = "raw"
+
+	MaterializationMaterializedView MaterializationType = "materialized_view"
+	MaterializationSeed             MaterializationType = "seed"
+	MaterializationSource           MaterializationType = "source"
+	MaterializationExport           MaterializationType = "export"
 )
 
 type NodeState string
@@ -28,6 +33,7 @@ const (
 	TestStatusInProgress TestStatus = "IN_PROGRESS"
 	TestStatusFailed     TestStatus = "FAILED"
 	TestStatusSuccess    TestStatus = "SUCCESS"
+	TestStatusWarn       TestStatus = "WARN" // Failing rows of a test with severity warn or under the error_if threshold
 )
 
 type DagNodeDTO struct {
@@ -42,21 +48,55 @@ type DagNodeDTO struct {
 	ConnectionName        string              `json:"connectionName"`
 	IsDataFramed          bool                `json:"isDataFramed"`
 	PersistInputs         bool                `json:"persistInputs"`
+	Grants                map[string][]string `json:"grants,omitempty"` // Grants applied after materialization (PostgreSQL only)
+	Atomic                bool                `json:"atomic,omitempty"` // Executed in one transaction, rolled back entirely on error (PostgreSQL only)
 	Tests                 []string            `json:"tests"`
 	State                 NodeState           `json:"state"`
 	TotalTests            int                 `json:"totalTests"`
 	SuccessfulTests       int                 `json:"successfulTests"`
-	LastExecutionDuration int64               `json:"lastExecutionDuration"` // Duration in milliseconds
-	LastTestsDuration     int64               `json:"lastTestsDuration"`     // Duration of tests execution in milliseconds
+	WarnedTests           int                 `json:"warnedTests,omitempty"`
+	LastExecutionDuration int64               `json:"lastExecutionDuration"`    // Duration in milliseconds
+	LastTestsDuration     int64               `json:"lastTestsDuration"`        // Duration of tests execution in milliseconds
+	Attempts              int                 `json:"attempts,omitempty"`       // Execution attempts of the last run, more than 1 after retries
+	Retries               int                 `json:"retries,omitempty"`        // Retries allowed by the profile
+	SourceRelation        string              `json:"sourceRelation,omitempty"` // Table of a source, "schema.table"
+	Freshness             *FreshnessDTO       `json:"freshness,omitempty"`      // Freshness thresholds of a source
+	Export                *ExportDTO          `json:"export,omitempty"`         // Files of an export model
+	Columns               []ColumnDTO         `json:"columns,omitempty"`        // Contract of the relation
+	EnforceTypes          bool                `json:"enforceTypes,omitempty"`   // Columns are cast to the types of the contract
+	Tags                  []string            `json:"tags,omitempty"`           // Tags of the profile, used by --select and --exclude
+	Disabled              bool                `json:"disabled,omitempty"`       // Disabled models are never executed
 	TaskGroupIndex        int                 `json:"TaskGroupIndex"`
 }
 
+type FreshnessDTO struct {
+	LoadedAtField string `json:"loadedAtField"`
+	WarnAfter     string `json:"warnAfter,omitempty"`
+	ErrorAfter    string `json:"errorAfter,omitempty"`
+}
+
+type ExportDTO struct {
+	Format      string   `json:"format"`
+	Path        string   `json:"path"` // Template, rendered at runtime
+	PartitionBy []string `json:"partitionBy,omitempty"`
+}
+
+type ColumnDTO struct {
+	Name        string `json:"name"`
+	Type        string `json:"type,omitempty"`
+	Nullable    bool   `json:"nullable"`
+	Description string `json:"description,omitempty"`
+}
+
 type TestProfileDTO struct {
 	Name           string `json:"name"`
 	Description    string `json:"description"`
 	SQL            string `json:"sql"`
 	ConnectionName string `json:"connectionName"`
 	ConnectionType string `json:"connectionType"`
+	Severity       string `json:"severity,omitempty"` // error (default) or warn
+	ErrorIf        string `json:"errorIf,omitempty"`  // Condition on the failing rows, "!= 0" by default
+	WarnIf         string `json:"warnIf,omitempty"`   // Condition on the failing rows, "!= 0" by default
 }
 
 type DagExecutionStatus string
@@ -73,7 +113,7 @@ const (
 // TestResultDTO represents a test result in API responses
 type TestResultDTO struct {
 	TestName   string `json:"testName"`
-	Status     string `json:"status"` // SUCCESS, FAILED, NOT_FOUND
+	Status     string `json:"status"` // SUCCESS, WARN, FAILED, NOT_FOUND
 	ErrorMsg   string `json:"error,omitempty"`
 	DurationMs int64  `json:"durationMs"`
 }
@@ -88,6 +128,7 @@ type NodeStatusDTO struct {
 	Message         string          `json:"message,omitempty"`
 	TotalTests      int             `json:"totalTests"`
 	PassedTests     int             `json:"passedTests"`
+	WarnedTests     int             `json:"warnedTests"`
 	FailedTests     int             `json:"failedTests"`
 	TestResults     []TestResultDTO `json:"testResults,omitempty"`
 }
@@ -109,6 +150,15 @@ type DagRunRequestDTO struct {
 	TaskId   string                 `json:"taskId"`
 	TaskUUID string                 `json:"taskUuid,omitempty"`
 	Data     map[string]interface{} `json:"data"`
+	// Vars override the vars of profile.yaml for this run
+	Vars map[string]interface{} `json:"vars,omitempty"`
+	// RunDate, IntervalStart and IntervalEnd are dates (2006-01-02) or RFC 3339 timestamps
+	RunDate       string `json:"runDate,omitempty"`
+	IntervalStart string `json:"intervalStart,omitempty"`
+	IntervalEnd   string `json:"intervalEnd,omitempty"`
+	// FullRefresh rebuilds the incremental models from scratch, the FullRefreshModels or all of them
+	FullRefresh       bool     `json:"fullRefresh,omitempty"`
+	FullRefreshModels []string `json:"fullRefreshModels,omitempty"`
 }
 
 // TaskSummaryDTO represents the summary of an entire task execution (identified by TaskId)
@@ -145,6 +195,8 @@ type AssetExecuteResponseDTO struct {
 	Result          interface{} `json:"result,omitempty"`
 	Columns         []string    `json:"columns,omitempty"` // Column order as returned by the SQL query (result rows are JSON objects with alphabetically sorted keys)
 	Error           string      `json:"error,omitempty"`
+	RolledBack      bool        `json:"rolledBack,omitempty"` // The failed execution of an atomic asset left no changes
+	Attempts        int         `json:"attempts,omitempty"`   // Number of execution attempts, more than 1 after retries
 	UpstreamsUsed   []string    `json:"upstreamsUsed"`
 	TotalRecords    int         `json:"totalRecords,omitempty"`
 	// Offset          int         `json:"offset,omitempty"`
@@ -32,7 +32,7 @@ type relationMaintenanceOp struct {
 }
 
 // resolveDropSQL is the drop policy: DROP TABLE for table/incremental models,
-// DROP VIEW for view models, not applicable to custom/raw. Package-level and pure
+// DROP [MATERIALIZED] VIEW for view models, not applicable to custom/raw. Package-level and pure
 // so the materialization branching can be unit-tested without a DB or DAG.
 func resolveDropSQL(d *models.SQLModelDescriptor) (string, bool) {
 	switch d.ModelProfile.Materialization {
@@ -40,6 +40,8 @@ func resolveDropSQL(d *models.SQLModelDescriptor) (string, bool) {
 		return d.DropTableSQL, true
 	case configs.MAT_VIEW:
 		return d.DropViewSQL, true
+	case configs.MAT_MATERIALIZED_VIEW:
+		return d.DropMaterializedViewSQL, true
 	default:
 		return "", false
 	}
@@ -14,6 +14,8 @@ func descriptorFor(mat configs.MatType) *models.SQLModelDescriptor {
 		DropViewSQL:      "drop view dds.some_model",
 		TruncateTableSQL: "truncate table dds.some_model",
 		ModelProfile:     &configs.ModelProfile{Materialization: mat},
+
+		DropMaterializedViewSQL: "drop materialized view dds.some_model",
 	}
 }
 
@@ -26,6 +28,7 @@ func TestResolveDropSQL(t *testing.T) {
 		{configs.MAT_TABLE, "drop table dds.some_model", true},
 		{configs.MAT_INCREMENTAL, "drop table dds.some_model", true},
 		{configs.MAT_VIEW, "drop view dds.some_model", true},
+		{configs.MAT_MATERIALIZED_VIEW, "drop materialized view dds.some_model", true},
 		{configs.MAT_CUSTOM, "", false},
 		{configs.MAT_RAW, "", false},
 	}
@@ -46,6 +49,7 @@ func TestResolveTruncateSQL(t *testing.T) {
 		{configs.MAT_TABLE, "truncate table dds.some_model", true},
 		{configs.MAT_INCREMENTAL, "truncate table dds.some_model", true},
 		{configs.MAT_VIEW, "", false},
+		{configs.MAT_MATERIALIZED_VIEW, "", false},
 		{configs.MAT_CUSTOM, "", false},
 		{configs.MAT_RAW, "", false},
 	}
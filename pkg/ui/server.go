@@ -22,10 +22,12 @@ import (
 	"net/http"
 	"os"
 	"strconv"
+	"time"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	"github.com/go-teal/teal/pkg/dags"
+	"github.com/go-teal/teal/pkg/processing"
 	"github.com/go-teal/teal/pkg/services/debugging"
 	"github.com/google/uuid"
 	"github.com/rs/zerolog/log"
@@ -245,8 +247,39 @@ func (s *UIServer) handleDagRun(c *gin.Context) {
 		return
 	}
 
+	// Vars must be declared in profile.yaml, the interval must not be empty
+	options := &processing.RunOptions{
+		Vars:              request.Vars,
+		FullRefresh:       request.FullRefresh,
+		FullRefreshModels: request.FullRefreshModels,
+	}
+	for _, date := range []struct {
+		field string
+		value string
+		date  *time.Time
+	}{
+		{"runDate", request.RunDate, &options.RunDate},
+		{"intervalStart", request.IntervalStart, &options.IntervalStart},
+		{"intervalEnd", request.IntervalEnd, &options.IntervalEnd},
+	} {
+		parsed, err := processing.ParseRunDate(date.value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": date.field + ": " + err.Error()})
+			return
+		}
+		*date.date = parsed
+	}
+	if _, err := options.Resolve(time.Now()); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := s.debuggingService.ValidateRunOptions(options); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	// Execute DAG with timeout
-	responseChan := s.debuggingService.ExecuteDag(request.TaskId, request.Data)
+	responseChan := s.debuggingService.ExecuteDag(request.TaskId, request.Data, options)
 
 	// Wait for response (will timeout after 10 seconds as configured in ExecuteDag)
 	response := <-responseChan